@@ -1,29 +1,68 @@
 package main
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hubenschmidt/go-fissio"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := commands[os.Args[1]]; ok {
+			runCommand(os.Args[1], run, os.Args[2:])
+			return
+		}
+	}
+
 	client := fissio.NewUnifiedClient(fissio.UnifiedConfig{
 		OpenAIKey:    os.Getenv("OPENAI_API_KEY"),
 		AnthropicKey: os.Getenv("ANTHROPIC_API_KEY"),
 		OllamaURL:    getEnvOr("OLLAMA_URL", "http://localhost:11434/v1"),
+		AWSRegion:    os.Getenv("AWS_REGION"),
+		AWSProfile:   os.Getenv("AWS_PROFILE"),
+		CohereKey:    os.Getenv("COHERE_API_KEY"),
+
+		HuggingFaceKey: os.Getenv("HF_TOKEN"),
+		OpenRouterKey:  os.Getenv("OPENROUTER_API_KEY"),
 	})
 
+	var sessionTTL time.Duration
+	if v := os.Getenv("FISSIO_SESSION_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Error("invalid FISSIO_SESSION_TTL", slog.String("value", v), slog.Any("error", err))
+			os.Exit(1)
+		}
+		sessionTTL = ttl
+	}
+
 	srv, err := fissio.NewServer(fissio.ServerConfig{
 		Client:      client,
 		OllamaURL:   getEnvOr("OLLAMA_URL", "http://localhost:11434"),
 		DatabaseDSN: os.Getenv("DATABASE_URL"),
+		PipelineDir: os.Getenv("FISSIO_PIPELINE_DIR"),
+		PostgresConfig: fissio.PostgresConfig{
+			SchemaPrefix: os.Getenv("FISSIO_PG_SCHEMA"),
+		},
+		Auth: fissio.AuthConfig{
+			APIKeys:   splitEnv("FISSIO_API_KEYS"),
+			AdminKeys: splitEnv("FISSIO_ADMIN_KEYS"),
+			JWTSecret: os.Getenv("FISSIO_JWT_SECRET"),
+		},
+		SessionTTL: sessionTTL,
 	})
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		slog.Error("failed to create server", slog.Any("error", err))
+		os.Exit(1)
 	}
 	defer srv.Close()
+	reloadOnSIGHUP(srv)
 
 	// In dev mode (DEV=1), serve only API - client runs separately on :3001
 	// In prod mode, serve embedded editor at /
@@ -36,8 +75,24 @@ func main() {
 	}
 
 	addr := getEnvOr("ADDR", ":8000")
-	log.Printf("Starting fissio server on http://localhost%s", addr)
-	log.Fatal(http.ListenAndServe(addr, handler))
+	slog.Info("starting fissio server", slog.String("addr", "http://localhost"+addr))
+	if err := http.ListenAndServe(addr, handler); err != nil {
+		slog.Error("server stopped", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// reloadOnSIGHUP re-reads FISSIO_PIPELINE_DIR whenever the process gets SIGHUP.
+func reloadOnSIGHUP(srv *fissio.Server) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := srv.Reload(); err != nil {
+				slog.Error("pipeline template reload failed", slog.Any("error", err))
+			}
+		}
+	}()
 }
 
 func getEnvOr(key, fallback string) string {
@@ -46,3 +101,13 @@ func getEnvOr(key, fallback string) string {
 	}
 	return fallback
 }
+
+func splitEnv(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion is the archive format written by ExportPipeline.
+const ArchiveVersion = 1
+
+// RedactedValue replaces credential-like metadata values in exported archives.
+const RedactedValue = "[REDACTED]"
+
+type pipelineArchive struct {
+	Version    int             `json:"version"`
+	ExportedAt int64           `json:"exported_at"` // unix millis
+	Pipeline   *PipelineConfig `json:"pipeline"`
+}
+
+// credentialKeys are metadata key fragments whose values are redacted on export.
+var credentialKeys = []string{"api_key", "apikey", "token", "secret", "password", "credential", "authorization"}
+
+// ExportPipeline serializes cfg to a gzipped JSON archive for sharing between
+// instances. Metadata values under credential-like keys are redacted, and node
+// prompts are cleared unless prompts is true. cfg itself is left unchanged.
+func ExportPipeline(cfg *PipelineConfig, prompts bool) ([]byte, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal pipeline: %w", err)
+	}
+	var export PipelineConfig
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("copy pipeline: %w", err)
+	}
+	export.sanitize(prompts)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	archive := pipelineArchive{Version: ArchiveVersion, ExportedAt: time.Now().UnixMilli(), Pipeline: &export}
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return nil, fmt.Errorf("encode archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportPipeline reads an archive written by ExportPipeline.
+func ImportPipeline(data []byte) (*PipelineConfig, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress archive: %w", err)
+	}
+
+	var archive pipelineArchive
+	if err := json.Unmarshal(raw, &archive); err != nil {
+		return nil, fmt.Errorf("decode archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d (want %d)", archive.Version, ArchiveVersion)
+	}
+	if archive.Pipeline == nil {
+		return nil, fmt.Errorf("archive has no pipeline")
+	}
+	return archive.Pipeline, nil
+}
+
+func (p *PipelineConfig) sanitize(prompts bool) {
+	redactCredentials(p.Metadata)
+	for _, n := range p.Nodes {
+		if !prompts {
+			n.Prompt = ""
+			n.SystemPrompt = ""
+		}
+		redactCredentials(n.Metadata)
+		if n.SubPipeline != nil {
+			n.SubPipeline.sanitize(prompts)
+		}
+	}
+}
+
+// redactCredentials replaces values under credential-like keys, descending
+// into nested objects and arrays.
+func redactCredentials(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if isCredentialKey(k) {
+				v[k] = RedactedValue
+				continue
+			}
+			redactCredentials(val)
+		}
+	case []any:
+		for _, val := range v {
+			redactCredentials(val)
+		}
+	}
+}
+
+func isCredentialKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, c := range credentialKeys {
+		if strings.Contains(key, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,15 @@
 package config
 
-import "github.com/hubenschmidt/go-fissio/core"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
 
 type PipelineBuilder struct {
 	config *PipelineConfig
+	rules  []ValidationRule
 }
 
 type NodeBuilder struct {
@@ -27,18 +33,25 @@ func (b *PipelineBuilder) Node(id string, nodeType NodeType) *NodeBuilder {
 	return &NodeBuilder{pipeline: b, node: node}
 }
 
+// SubPipeline starts a node that runs sub as a nested pipeline.
+func (b *PipelineBuilder) SubPipeline(id string, sub *PipelineConfig) *NodeBuilder {
+	node := NewNodeConfig(id, NodeSubpipeline)
+	node.SubPipeline = sub
+	return &NodeBuilder{pipeline: b, node: node}
+}
+
 func (b *PipelineBuilder) Edge(from, to string) *PipelineBuilder {
 	b.config.AddEdge(from, to)
 	return b
 }
 
 func (b *PipelineBuilder) ConditionalEdge(from, to, condition string) *PipelineBuilder {
-	b.config.Edges = append(b.config.Edges, EdgeConfig{
-		From:      EdgeEndpoint{Node: from},
-		To:        EdgeEndpoint{Node: to},
-		Type:      EdgeConditional,
-		Condition: condition,
-	})
+	b.config.AddConditionalEdge(from, to, condition)
+	return b
+}
+
+func (b *PipelineBuilder) FanOutEdge(from, to string) *PipelineBuilder {
+	b.config.AddFanOutEdge(from, to)
 	return b
 }
 
@@ -47,8 +60,27 @@ func (b *PipelineBuilder) EntryNode(id string) *PipelineBuilder {
 	return b
 }
 
+// Rules adds validation rules checked by Build and BuildSafe on top of the defaults.
+func (b *PipelineBuilder) Rules(rules ...ValidationRule) *PipelineBuilder {
+	b.rules = append(b.rules, rules...)
+	return b
+}
+
+// Build validates the pipeline and panics with a *ValidationError on violations.
 func (b *PipelineBuilder) Build() *PipelineConfig {
-	return b.config
+	cfg, err := b.BuildSafe()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// BuildSafe validates the pipeline and returns the violations instead of panicking.
+func (b *PipelineBuilder) BuildSafe() (*PipelineConfig, error) {
+	if err := b.config.Validate(b.rules...); err != nil {
+		return nil, err
+	}
+	return b.config, nil
 }
 
 func (n *NodeBuilder) Prompt(prompt string) *NodeBuilder {
@@ -56,6 +88,18 @@ func (n *NodeBuilder) Prompt(prompt string) *NodeBuilder {
 	return n
 }
 
+// SystemPrompt sets the node's system instruction, which takes precedence over Prompt.
+func (n *NodeBuilder) SystemPrompt(prompt string) *NodeBuilder {
+	n.node.SystemPrompt = prompt
+	return n
+}
+
+// UserPromptTemplate sets the text/template that builds the user message from
+// the node input; it is the same setting as InputTemplate.
+func (n *NodeBuilder) UserPromptTemplate(tmpl string) *NodeBuilder {
+	return n.InputTemplate(tmpl)
+}
+
 func (n *NodeBuilder) Model(name string) *NodeBuilder {
 	n.node.Model = core.DefaultModelConfig(name)
 	return n
@@ -76,6 +120,12 @@ func (n *NodeBuilder) MaxIterations(max int) *NodeBuilder {
 	return n
 }
 
+// ToolConcurrency lets a worker run up to limit tool calls from one turn in parallel.
+func (n *NodeBuilder) ToolConcurrency(limit int) *NodeBuilder {
+	n.node.ToolConcurrency = limit
+	return n
+}
+
 func (n *NodeBuilder) NextNodes(nodes ...string) *NodeBuilder {
 	n.node.NextNodes = append(n.node.NextNodes, nodes...)
 	return n
@@ -94,6 +144,70 @@ func (n *NodeBuilder) Meta(key string, val any) *NodeBuilder {
 	return n
 }
 
+func (n *NodeBuilder) ConditionNode(id string) *NodeBuilder {
+	n.node.ConditionNode = id
+	return n
+}
+
+// Timeout bounds the node's execution time, rounded up to whole seconds.
+func (n *NodeBuilder) Timeout(d time.Duration) *NodeBuilder {
+	n.node.TimeoutSecs = int((d + time.Second - 1) / time.Second)
+	return n
+}
+
+func (n *NodeBuilder) ResponseSchema(schema json.RawMessage) *NodeBuilder {
+	n.node.ResponseSchema = schema
+	return n
+}
+
+// InputTemplate sets a text/template that replaces the node's input before execution.
+func (n *NodeBuilder) InputTemplate(tmpl string) *NodeBuilder {
+	n.node.InputTemplate = tmpl
+	return n
+}
+
+// MaxInputTokens truncates the node's input to about max tokens before execution.
+func (n *NodeBuilder) MaxInputTokens(max int) *NodeBuilder {
+	n.node.MaxInputTokens = max
+	return n
+}
+
+// TruncationStrategy selects which portion MaxInputTokens drops: TruncateEnd, TruncateMiddle or TruncateStart.
+func (n *NodeBuilder) TruncationStrategy(strategy string) *NodeBuilder {
+	n.node.TruncationStrategy = strategy
+	return n
+}
+
+// JoinStrategy selects how a join node combines its sources: JoinConcat or JoinLLMMerge.
+func (n *NodeBuilder) JoinStrategy(strategy string) *NodeBuilder {
+	n.node.JoinStrategy = strategy
+	return n
+}
+
+// ChunkSize sets the maximum characters a splitter node sends to each target.
+func (n *NodeBuilder) ChunkSize(size int) *NodeBuilder {
+	n.node.ChunkSize = size
+	return n
+}
+
+// Delimiter sets the separator an aggregator node places between upstream outputs.
+func (n *NodeBuilder) Delimiter(delim string) *NodeBuilder {
+	n.node.Delimiter = delim
+	return n
+}
+
+// OutputParser extracts fields from the node's output into NodeOutput.Metadata.
+func (n *NodeBuilder) OutputParser(parser OutputParser) *NodeBuilder {
+	n.node.OutputParser = &parser
+	return n
+}
+
+// OutputTransform sets a text/template, with TransformFuncs, that rewrites the node's output.
+func (n *NodeBuilder) OutputTransform(tmpl string) *NodeBuilder {
+	n.node.OutputTransform = tmpl
+	return n
+}
+
 func (n *NodeBuilder) Done() *PipelineBuilder {
 	n.pipeline.config.AddNode(n.node)
 	return n.pipeline
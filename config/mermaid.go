@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Mermaid renders the pipeline as a Mermaid "graph TD" diagram. Nodes are
+// boxes labeled with their ID and type (plus tools for workers); conditional
+// edges are dashed and labeled with their condition.
+func (p *PipelineConfig) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+
+	ids := make(map[string]string, len(p.Nodes))
+	for i, n := range p.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d_%s", i, mermaidUnsafe.ReplaceAllString(n.ID, "_"))
+	}
+	mermaidID := func(nodeID string) string {
+		if id, ok := ids[nodeID]; ok {
+			return id
+		}
+		return "x_" + mermaidUnsafe.ReplaceAllString(nodeID, "_")
+	}
+
+	for _, n := range p.Nodes {
+		label := fmt.Sprintf("%s<br/>(%s)", n.ID, n.Type)
+		if n.Type == NodeWorker && len(n.Tools) > 0 {
+			label += "<br/>tools: " + strings.Join(n.Tools, ", ")
+		}
+		fmt.Fprintf(&sb, "    %s[\"%s\"]\n", mermaidID(n.ID), mermaidEscape(label))
+	}
+
+	for _, e := range p.Edges {
+		from, to := mermaidID(e.From.Node), mermaidID(e.To.Node)
+		switch {
+		case e.Type == EdgeConditional && e.Condition != "":
+			fmt.Fprintf(&sb, "    %s -.->|\"%s\"| %s\n", from, mermaidEscape(e.Condition), to)
+		case e.Type == EdgeConditional:
+			fmt.Fprintf(&sb, "    %s -.-> %s\n", from, to)
+		case e.Type == EdgeLoop:
+			fmt.Fprintf(&sb, "    %s -->|loop| %s\n", from, to)
+		case e.Type == EdgeFanOut:
+			fmt.Fprintf(&sb, "    %s ==> %s\n", from, to)
+		default:
+			fmt.Fprintf(&sb, "    %s --> %s\n", from, to)
+		}
+	}
+
+	return sb.String()
+}
+
+// mermaidEscape replaces characters that would end a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
@@ -1,29 +1,105 @@
 package config
 
-import "github.com/hubenschmidt/go-fissio/core"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
 
 type EdgeEndpoint struct {
-	Node string `json:"node"`
-	Port string `json:"port,omitempty"`
+	Node string `json:"node" yaml:"node"`
+	Port string `json:"port,omitempty" yaml:"port,omitempty"`
 }
 
 type EdgeConfig struct {
-	From      EdgeEndpoint `json:"from"`
-	To        EdgeEndpoint `json:"to"`
-	Type      EdgeType     `json:"type"`
-	Condition string       `json:"condition,omitempty"`
+	From      EdgeEndpoint `json:"from" yaml:"from"`
+	To        EdgeEndpoint `json:"to" yaml:"to"`
+	Type      EdgeType     `json:"type" yaml:"type"`
+	Condition string       `json:"condition,omitempty" yaml:"condition,omitempty"`
 }
 
 type NodeConfig struct {
-	ID          string           `json:"id"`
-	Type        NodeType         `json:"type"`
-	Prompt      string           `json:"prompt,omitempty"`
-	Model       core.ModelConfig `json:"model,omitempty"`
-	Tools       []string         `json:"tools,omitempty"`
-	MaxIter     int              `json:"max_iter,omitempty"`
-	NextNodes   []string         `json:"next_nodes,omitempty"`
-	TargetNodes []string         `json:"target_nodes,omitempty"`
-	Metadata    map[string]any   `json:"metadata,omitempty"`
+	ID          string           `json:"id" yaml:"id"`
+	Type        NodeType         `json:"type" yaml:"type"`
+	Prompt      string           `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Model       core.ModelConfig `json:"model,omitempty" yaml:"model,omitempty"`
+	Tools       []string         `json:"tools,omitempty" yaml:"tools,omitempty"`
+	MaxIter     int              `json:"max_iter,omitempty" yaml:"max_iter,omitempty"`
+	NextNodes   []string         `json:"next_nodes,omitempty" yaml:"next_nodes,omitempty"`
+	TargetNodes []string         `json:"target_nodes,omitempty" yaml:"target_nodes,omitempty"`
+	Metadata    map[string]any   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	SubPipeline *PipelineConfig  `json:"sub_pipeline,omitempty" yaml:"sub_pipeline,omitempty"`
+	TimeoutSecs int              `json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty"`
+
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"` // Optional: alias of TimeoutSecs, used when TimeoutSecs is 0
+
+	ConditionNode string `json:"condition_node,omitempty" yaml:"condition_node,omitempty"` // Loop: evaluator returning "continue" or "done"
+
+	ToolConcurrency int `json:"tool_concurrency,omitempty" yaml:"tool_concurrency,omitempty"` // Worker: run up to this many of a turn's tool calls at once (default: 1, sequential)
+
+	ResponseSchema json.RawMessage `json:"response_schema,omitempty" yaml:"-"` // Optional: JSON schema for OpenAI strict structured outputs; a mapping in YAML, see NodeConfig.UnmarshalYAML
+
+	InputTemplate string `json:"input_template,omitempty" yaml:"input_template,omitempty"` // Optional: text/template for the node input, e.g. "{{.Content}} in {{.Vars.language}}"
+
+	SystemPrompt string `json:"system_prompt,omitempty" yaml:"system_prompt,omitempty"` // Optional: system instruction; takes precedence over Prompt
+
+	MaxInputTokens     int    `json:"max_input_tokens,omitempty" yaml:"max_input_tokens,omitempty"`       // Optional: truncate the input to about this many tokens
+	TruncationStrategy string `json:"truncation_strategy,omitempty" yaml:"truncation_strategy,omitempty"` // Optional: portion dropped by MaxInputTokens (default: TruncateEnd)
+
+	OutputParser    *OutputParser `json:"output_parser,omitempty" yaml:"output_parser,omitempty"`       // Optional: extracts fields from the output into NodeOutput.Metadata
+	OutputTransform string        `json:"output_transform,omitempty" yaml:"output_transform,omitempty"` // Optional: text/template replacing the output, e.g. `{{.Content | jsonField "answer"}}`; see TransformFuncs
+
+	JoinStrategy string `json:"join_strategy,omitempty" yaml:"join_strategy,omitempty"` // Join: JoinConcat (default) or JoinLLMMerge
+
+	ChunkSize int    `json:"chunk_size,omitempty" yaml:"chunk_size,omitempty"` // Splitter: max characters per chunk (default: 2000)
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`   // Aggregator: separator between upstream outputs (default: blank line)
+}
+
+// OutputParser extracts structured fields from a node's text output.
+type OutputParser struct {
+	Type    string `json:"type" yaml:"type"`                           // ParserJSON, ParserJSONBlock, ParserRegex or ParserPassthrough
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"` // ParserRegex: named groups become fields, e.g. `score: (?P<score>\d+)`
+}
+
+// Output parser types.
+const (
+	ParserJSON        = "json"        // the whole output is a JSON object
+	ParserJSONBlock   = "json_block"  // JSON inside a ```json fence, or the whole output
+	ParserRegex       = "regex"       // named capture groups of Pattern
+	ParserPassthrough = "passthrough" // no fields extracted
+)
+
+// Join strategies combine the outputs a join node collects from its sources.
+const (
+	JoinConcat   = "concat"    // outputs separated by blank lines
+	JoinLLMMerge = "llm_merge" // outputs synthesized by the node's model, guided by Prompt
+)
+
+// Truncation strategies name the portion of an over-long input that is dropped.
+const (
+	TruncateEnd    = "end"
+	TruncateMiddle = "middle"
+	TruncateStart  = "start"
+)
+
+// SystemInstruction returns the node's system prompt: SystemPrompt when set,
+// otherwise Prompt.
+func (n *NodeConfig) SystemInstruction() string {
+	if n.SystemPrompt != "" {
+		return n.SystemPrompt
+	}
+	return n.Prompt
+}
+
+// Timeout returns the node's execution limit from TimeoutSecs, or else
+// TimeoutSeconds; zero means none.
+func (n *NodeConfig) Timeout() time.Duration {
+	secs := n.TimeoutSecs
+	if secs == 0 {
+		secs = n.TimeoutSeconds
+	}
+	return time.Duration(secs) * time.Second
 }
 
 func NewNodeConfig(id string, nodeType NodeType) *NodeConfig {
@@ -31,7 +107,7 @@ func NewNodeConfig(id string, nodeType NodeType) *NodeConfig {
 		ID:   id,
 		Type: nodeType,
 	}
-	if nodeType == NodeWorker {
+	if nodeType == NodeWorker || nodeType == NodeLoop {
 		cfg.MaxIter = 10
 	}
 	return cfg
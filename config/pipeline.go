@@ -6,13 +6,13 @@ import (
 )
 
 type PipelineConfig struct {
-	ID          string        `json:"id"`
-	Name        string        `json:"name"`
-	Description string        `json:"description,omitempty"`
-	Nodes       []*NodeConfig `json:"nodes"`
-	Edges       []EdgeConfig  `json:"edges"`
-	EntryNode   string        `json:"entry_node,omitempty"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
+	ID          string         `json:"id" yaml:"id"`
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Nodes       []*NodeConfig  `json:"nodes" yaml:"nodes"`
+	Edges       []EdgeConfig   `json:"edges" yaml:"edges"`
+	EntryNode   string         `json:"entry_node,omitempty" yaml:"entry_node,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 func NewPipelineConfig(id, name string) *PipelineConfig {
@@ -38,6 +38,27 @@ func (p *PipelineConfig) AddEdge(from, to string) *PipelineConfig {
 	return p
 }
 
+// AddConditionalEdge adds an edge taken only when the source router's route matches condition.
+func (p *PipelineConfig) AddConditionalEdge(from, to, condition string) *PipelineConfig {
+	p.Edges = append(p.Edges, EdgeConfig{
+		From:      EdgeEndpoint{Node: from},
+		To:        EdgeEndpoint{Node: to},
+		Type:      EdgeConditional,
+		Condition: condition,
+	})
+	return p
+}
+
+// AddFanOutEdge adds an edge from a fan-out node to one of the branches it runs concurrently.
+func (p *PipelineConfig) AddFanOutEdge(from, to string) *PipelineConfig {
+	p.Edges = append(p.Edges, EdgeConfig{
+		From: EdgeEndpoint{Node: from},
+		To:   EdgeEndpoint{Node: to},
+		Type: EdgeFanOut,
+	})
+	return p
+}
+
 func (p *PipelineConfig) GetNode(id string) *NodeConfig {
 	for _, n := range p.Nodes {
 		if n.ID == id {
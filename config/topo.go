@@ -0,0 +1,73 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// TopologicalOrder returns node IDs in dependency order using Kahn's algorithm.
+// Ties are broken by declaration order so the result is deterministic.
+// Loop edges are treated as back-edges and ignored. A cycle among the
+// remaining edges yields core.ErrCyclicDependency.
+func (p *PipelineConfig) TopologicalOrder() ([]string, error) {
+	index := make(map[string]int, len(p.Nodes))
+	for i, n := range p.Nodes {
+		index[n.ID] = i
+	}
+
+	inDegree := make(map[string]int, len(p.Nodes))
+	successors := make(map[string][]string)
+	for _, e := range p.Edges {
+		_, fromOK := index[e.From.Node]
+		_, toOK := index[e.To.Node]
+		if !fromOK || !toOK || e.Type == EdgeLoop {
+			continue
+		}
+		successors[e.From.Node] = append(successors[e.From.Node], e.To.Node)
+		inDegree[e.To.Node]++
+	}
+
+	var ready []string
+	for _, n := range p.Nodes {
+		if inDegree[n.ID] == 0 {
+			ready = append(ready, n.ID)
+		}
+	}
+
+	order := make([]string, 0, len(p.Nodes))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			return index[ready[i]] < index[ready[j]]
+		})
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		for _, next := range successors[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(order) != len(p.Nodes) {
+		return nil, core.ErrCyclicDependency
+	}
+	return order, nil
+}
+
+// TopologicalSort is TopologicalOrder returning the nodes themselves. It is
+// the order the engine uses to schedule nodes that become ready together.
+func (p *PipelineConfig) TopologicalSort() ([]*NodeConfig, error) {
+	order, err := p.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*NodeConfig, len(order))
+	for i, id := range order {
+		nodes[i] = p.GetNode(id)
+	}
+	return nodes, nil
+}
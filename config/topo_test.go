@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// topoPipeline declares nodes in the given order and adds an edge for each
+// [from, to] pair.
+func topoPipeline(nodes []string, edges [][2]string) *PipelineConfig {
+	p := NewPipelineConfig("topo", "Topo")
+	for _, id := range nodes {
+		p.AddNode(NewNodeConfig(id, NodeLLM))
+	}
+	for _, e := range edges {
+		p.AddEdge(e[0], e[1])
+	}
+	return p
+}
+
+func TestTopologicalSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []string
+		edges [][2]string
+		want  []string
+	}{
+		{
+			name:  "linear",
+			nodes: []string{"a", "b", "c"},
+			edges: [][2]string{{"a", "b"}, {"b", "c"}},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "linear declared out of order",
+			nodes: []string{"c", "b", "a"},
+			edges: [][2]string{{"a", "b"}, {"b", "c"}},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "diamond",
+			nodes: []string{"start", "left", "right", "end"},
+			edges: [][2]string{{"start", "left"}, {"start", "right"}, {"left", "end"}, {"right", "end"}},
+			want:  []string{"start", "left", "right", "end"},
+		},
+		{
+			name:  "fan-out",
+			nodes: []string{"src", "x", "y", "z"},
+			edges: [][2]string{{"src", "x"}, {"src", "y"}, {"src", "z"}},
+			want:  []string{"src", "x", "y", "z"},
+		},
+		{
+			name:  "ties follow declaration order, not edge order",
+			nodes: []string{"src", "z", "y", "x"},
+			edges: [][2]string{{"src", "x"}, {"src", "y"}, {"src", "z"}},
+			want:  []string{"src", "z", "y", "x"},
+		},
+		{
+			name:  "independent roots follow declaration order",
+			nodes: []string{"b", "a", "c"},
+			edges: [][2]string{{"a", "c"}},
+			want:  []string{"b", "a", "c"},
+		},
+		{
+			name:  "edges to unknown nodes are ignored",
+			nodes: []string{"a", "b"},
+			edges: [][2]string{{"a", "b"}, {"b", "missing"}},
+			want:  []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := topoPipeline(tt.nodes, tt.edges)
+			for range 3 {
+				nodes, err := p.TopologicalSort()
+				if err != nil {
+					t.Fatalf("TopologicalSort error: %v", err)
+				}
+				got := make([]string, len(nodes))
+				for i, n := range nodes {
+					got[i] = n.ID
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Fatalf("TopologicalSort = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges [][2]string
+	}{
+		{"two nodes", [][2]string{{"a", "b"}, {"b", "a"}}},
+		{"self loop", [][2]string{{"a", "a"}}},
+		{"cycle after a valid prefix", [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := topoPipeline([]string{"a", "b", "c"}, tt.edges)
+			if _, err := p.TopologicalSort(); !errors.Is(err, core.ErrCyclicDependency) {
+				t.Errorf("TopologicalSort error = %v, want %v", err, core.ErrCyclicDependency)
+			}
+		})
+	}
+}
+
+func TestTopologicalSortIgnoresLoopEdges(t *testing.T) {
+	p := topoPipeline([]string{"a", "b"}, [][2]string{{"a", "b"}})
+	p.Edges = append(p.Edges, EdgeConfig{From: EdgeEndpoint{Node: "b"}, To: EdgeEndpoint{Node: "a"}, Type: EdgeLoop})
+
+	nodes, err := p.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort error: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].ID != "a" || nodes[1].ID != "b" {
+		t.Errorf("TopologicalSort = %v, want [a b]", nodes)
+	}
+}
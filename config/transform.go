@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// TransformFuncs are the functions available to NodeConfig.OutputTransform
+// templates. Each takes the text last so calls chain with pipes, e.g.
+//
+//	{{.Content | trimMarkdownFence | jsonField "answer" | truncate 200}}
+var TransformFuncs = template.FuncMap{
+	"jsonField":         jsonField,
+	"trimMarkdownFence": trimMarkdownFence,
+	"truncate":          truncate,
+}
+
+// ParseOutputTransform parses a node's OutputTransform with TransformFuncs.
+func ParseOutputTransform(n *NodeConfig) (*template.Template, error) {
+	return template.New(n.ID).Funcs(TransformFuncs).Parse(n.OutputTransform)
+}
+
+// jsonField returns the value at a dot-separated path in the JSON object s,
+// with strings unquoted and other values re-encoded as JSON.
+func jsonField(path, s string) (string, error) {
+	var v any
+	if err := json.Unmarshal([]byte(trimMarkdownFence(s)), &v); err != nil {
+		return "", fmt.Errorf("jsonField: %w", err)
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("jsonField: %q is not an object at %q", path, key)
+		}
+		if v, ok = obj[key]; !ok {
+			return "", fmt.Errorf("jsonField: no field %q", path)
+		}
+	}
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+var markdownFence = regexp.MustCompile("(?s)^```[\\w-]*[ \\t]*\\n?(.*?)\\n?```$")
+
+// trimMarkdownFence removes a code fence wrapping the whole of s, with any
+// language tag, and surrounding whitespace.
+func trimMarkdownFence(s string) string {
+	s = strings.TrimSpace(s)
+	if m := markdownFence.FindStringSubmatch(s); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return s
+}
+
+// truncate shortens s to at most n characters.
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
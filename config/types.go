@@ -12,6 +12,13 @@ const (
 	NodeEvaluator
 	NodeSynthesizer
 	NodeCoordinator
+	NodeParallel
+	NodeJoin
+	NodeEmbedder
+	NodeSubpipeline
+	NodeLoop
+	NodeFanOut
+	NodeSplitter
 )
 
 var nodeTypeNames = map[NodeType]string{
@@ -24,6 +31,13 @@ var nodeTypeNames = map[NodeType]string{
 	NodeEvaluator:    "evaluator",
 	NodeSynthesizer:  "synthesizer",
 	NodeCoordinator:  "coordinator",
+	NodeParallel:     "parallel",
+	NodeJoin:         "join",
+	NodeEmbedder:     "embedder",
+	NodeSubpipeline:  "subpipeline",
+	NodeLoop:         "loop",
+	NodeFanOut:       "fan_out",
+	NodeSplitter:     "splitter",
 }
 
 var nodeTypeValues = map[string]NodeType{
@@ -36,6 +50,13 @@ var nodeTypeValues = map[string]NodeType{
 	"evaluator":    NodeEvaluator,
 	"synthesizer":  NodeSynthesizer,
 	"coordinator":  NodeCoordinator,
+	"parallel":     NodeParallel,
+	"join":         NodeJoin,
+	"embedder":     NodeEmbedder,
+	"subpipeline":  NodeSubpipeline,
+	"loop":         NodeLoop,
+	"fan_out":      NodeFanOut,
+	"splitter":     NodeSplitter,
 }
 
 func (n NodeType) String() string {
@@ -68,12 +89,21 @@ const (
 	EdgeDefault EdgeType = iota
 	EdgeConditional
 	EdgeLoop
+	EdgeFanOut
 )
 
 var edgeTypeNames = map[EdgeType]string{
 	EdgeDefault:     "default",
 	EdgeConditional: "conditional",
 	EdgeLoop:        "loop",
+	EdgeFanOut:      "fan_out",
+}
+
+var edgeTypeValues = map[string]EdgeType{
+	"default":     EdgeDefault,
+	"conditional": EdgeConditional,
+	"loop":        EdgeLoop,
+	"fan_out":     EdgeFanOut,
 }
 
 func (e EdgeType) String() string {
@@ -82,3 +112,8 @@ func (e EdgeType) String() string {
 	}
 	return "unknown"
 }
+
+func ParseEdgeType(s string) (EdgeType, bool) {
+	et, ok := edgeTypeValues[s]
+	return et, ok
+}
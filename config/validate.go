@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ValidationError aggregates every rule violation found in a pipeline.
+type ValidationError struct {
+	Violations []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return fmt.Sprintf("invalid pipeline: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Violations
+}
+
+// ValidationRule checks one property of a pipeline and returns any violations.
+type ValidationRule func(p *PipelineConfig) []error
+
+// DefaultValidationRules are applied by Validate before any caller-supplied rules.
+var DefaultValidationRules = []ValidationRule{
+	RuleUniqueNodeIDs,
+	RuleLLMPrompt,
+	RuleToolNames,
+	RuleWorkerMaxIter,
+	RuleEdgeEndpoints,
+	RuleEntryNode,
+	RuleInputTemplates,
+	RuleOutputTransforms,
+	RuleTruncationStrategy,
+	RuleOutputParsers,
+	RuleJoinStrategy,
+}
+
+// Validate runs the default rules plus any extra ones and returns a
+// *ValidationError listing all violations, or nil if the pipeline is valid.
+func (p *PipelineConfig) Validate(extra ...ValidationRule) error {
+	var violations []error
+	for _, rule := range slices.Concat(DefaultValidationRules, extra) {
+		violations = append(violations, rule(p)...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// RuleUniqueNodeIDs rejects duplicate node IDs.
+func RuleUniqueNodeIDs(p *PipelineConfig) []error {
+	var errs []error
+	seen := make(map[string]bool, len(p.Nodes))
+	for _, n := range p.Nodes {
+		if seen[n.ID] {
+			errs = append(errs, fmt.Errorf("node %q: duplicate id", n.ID))
+		}
+		seen[n.ID] = true
+	}
+	return errs
+}
+
+// RuleLLMPrompt requires a prompt on LLM and worker nodes.
+func RuleLLMPrompt(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		if (n.Type == NodeLLM || n.Type == NodeWorker) && strings.TrimSpace(n.SystemInstruction()) == "" {
+			errs = append(errs, fmt.Errorf("node %q: %s node has no prompt", n.ID, n.Type))
+		}
+	}
+	return errs
+}
+
+// RuleToolNames requires tool names to be snake_case, e.g. "web_search".
+func RuleToolNames(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		for _, t := range n.Tools {
+			if !strings.Contains(t, "_") {
+				errs = append(errs, fmt.Errorf("node %q: tool name %q has no underscore", n.ID, t))
+			}
+		}
+	}
+	return errs
+}
+
+// RuleWorkerMaxIter requires worker nodes to allow at least one iteration.
+func RuleWorkerMaxIter(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		if n.Type == NodeWorker && n.MaxIter <= 0 {
+			errs = append(errs, fmt.Errorf("node %q: worker max_iter must be positive", n.ID))
+		}
+	}
+	return errs
+}
+
+// RuleEdgeEndpoints requires every edge to connect declared nodes.
+func RuleEdgeEndpoints(p *PipelineConfig) []error {
+	var errs []error
+	for _, e := range p.Edges {
+		if p.GetNode(e.From.Node) == nil {
+			errs = append(errs, fmt.Errorf("edge %s->%s: unknown source node", e.From.Node, e.To.Node))
+		}
+		if p.GetNode(e.To.Node) == nil {
+			errs = append(errs, fmt.Errorf("edge %s->%s: unknown target node", e.From.Node, e.To.Node))
+		}
+	}
+	return errs
+}
+
+// RuleEntryNode requires EntryNode, when set, to name a declared node.
+func RuleEntryNode(p *PipelineConfig) []error {
+	if p.EntryNode == "" || p.GetNode(p.EntryNode) != nil {
+		return nil
+	}
+	return []error{fmt.Errorf("entry node %q not found", p.EntryNode)}
+}
+
+// RuleInputTemplates requires node input templates to parse.
+func RuleInputTemplates(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		if n.InputTemplate == "" {
+			continue
+		}
+		if _, err := template.New(n.ID).Parse(n.InputTemplate); err != nil {
+			errs = append(errs, fmt.Errorf("node %q: invalid input_template: %w", n.ID, err))
+		}
+	}
+	return errs
+}
+
+// RuleOutputTransforms requires output transforms to parse as templates.
+func RuleOutputTransforms(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		if n.OutputTransform == "" {
+			continue
+		}
+		if _, err := ParseOutputTransform(n); err != nil {
+			errs = append(errs, fmt.Errorf("node %q: invalid output_transform: %w", n.ID, err))
+		}
+	}
+	return errs
+}
+
+// RuleTruncationStrategy requires truncation strategies to be known.
+func RuleTruncationStrategy(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		switch n.TruncationStrategy {
+		case "", TruncateEnd, TruncateMiddle, TruncateStart:
+		default:
+			errs = append(errs, fmt.Errorf("node %q: unknown truncation_strategy %q", n.ID, n.TruncationStrategy))
+		}
+	}
+	return errs
+}
+
+// RuleJoinStrategy requires join strategies to be known.
+func RuleJoinStrategy(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		switch n.JoinStrategy {
+		case "", JoinConcat, JoinLLMMerge:
+		default:
+			errs = append(errs, fmt.Errorf("node %q: unknown join_strategy %q", n.ID, n.JoinStrategy))
+		}
+	}
+	return errs
+}
+
+// RuleOutputParsers requires output parsers to have a known type and regex
+// parsers to have a pattern with at least one named group.
+func RuleOutputParsers(p *PipelineConfig) []error {
+	var errs []error
+	for _, n := range p.Nodes {
+		if n.OutputParser == nil {
+			continue
+		}
+		switch n.OutputParser.Type {
+		case ParserJSON, ParserJSONBlock, ParserPassthrough:
+		case ParserRegex:
+			re, err := regexp.Compile(n.OutputParser.Pattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("node %q: invalid output_parser pattern: %w", n.ID, err))
+			} else if !slices.ContainsFunc(re.SubexpNames(), func(name string) bool { return name != "" }) {
+				errs = append(errs, fmt.Errorf("node %q: output_parser pattern has no named groups", n.ID))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("node %q: unknown output_parser type %q", n.ID, n.OutputParser.Type))
+		}
+	}
+	return errs
+}
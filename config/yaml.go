@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML serializes the pipeline to YAML with node and edge types as names.
+func (p *PipelineConfig) ToYAML() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// LoadPipelineYAML reads a YAML pipeline definition from path.
+// ${VAR} tokens in node prompts and model names are expanded from the environment.
+func LoadPipelineYAML(path string) (*PipelineConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg PipelineConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.expandEnv()
+	return &cfg, nil
+}
+
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func (p *PipelineConfig) expandEnv() {
+	for _, n := range p.Nodes {
+		n.Prompt = expandEnvVars(n.Prompt)
+		n.SystemPrompt = expandEnvVars(n.SystemPrompt)
+		n.Model.Name = expandEnvVars(n.Model.Name)
+	}
+}
+
+// expandEnvVars replaces ${VAR} tokens only, leaving bare $ signs untouched.
+func expandEnvVars(s string) string {
+	return envVarPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return os.Getenv(envVarPattern.FindStringSubmatch(m)[1])
+	})
+}
+
+func (n NodeType) MarshalYAML() (any, error) {
+	return n.String(), nil
+}
+
+func (n *NodeType) UnmarshalYAML(value *yaml.Node) error {
+	nt, ok := ParseNodeType(value.Value)
+	if !ok {
+		return fmt.Errorf("unknown node type: %q", value.Value)
+	}
+	*n = nt
+	return nil
+}
+
+func (e EdgeType) MarshalYAML() (any, error) {
+	return e.String(), nil
+}
+
+func (e *EdgeType) UnmarshalYAML(value *yaml.Node) error {
+	et, ok := ParseEdgeType(value.Value)
+	if !ok {
+		return fmt.Errorf("unknown edge type: %q", value.Value)
+	}
+	*e = et
+	return nil
+}
+
+// MarshalYAML writes ResponseSchema as a YAML mapping rather than raw bytes.
+func (n NodeConfig) MarshalYAML() (any, error) {
+	type plain NodeConfig
+	out := struct {
+		plain          `yaml:",inline"`
+		ResponseSchema map[string]any `yaml:"response_schema,omitempty"`
+	}{plain: plain(n)}
+	if len(n.ResponseSchema) > 0 {
+		if err := json.Unmarshal(n.ResponseSchema, &out.ResponseSchema); err != nil {
+			return nil, fmt.Errorf("node %s: response_schema: %w", n.ID, err)
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalYAML reads ResponseSchema from a YAML mapping and stores it as JSON.
+func (n *NodeConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain NodeConfig
+	if err := value.Decode((*plain)(n)); err != nil {
+		return err
+	}
+	var extra struct {
+		ResponseSchema yaml.Node `yaml:"response_schema"`
+	}
+	if err := value.Decode(&extra); err != nil {
+		return err
+	}
+	if extra.ResponseSchema.Kind == 0 {
+		return nil
+	}
+	if extra.ResponseSchema.Kind != yaml.MappingNode {
+		return fmt.Errorf("node %s: response_schema must be a mapping", n.ID)
+	}
+	var schema map[string]any
+	if err := extra.ResponseSchema.Decode(&schema); err != nil {
+		return fmt.Errorf("node %s: response_schema: %w", n.ID, err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("node %s: response_schema: %w", n.ID, err)
+	}
+	n.ResponseSchema = data
+	return nil
+}
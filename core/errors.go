@@ -16,6 +16,8 @@ var (
 	ErrInvalidEdge      = errors.New("invalid edge configuration")
 	ErrTimeout          = errors.New("operation timed out")
 	ErrLLMRequest       = errors.New("LLM request failed")
+	ErrBudgetExceeded   = errors.New("cost budget exceeded")
+	ErrNoRoute          = errors.New("route matches no edge")
 )
 
 type AgentError struct {
@@ -1,5 +1,7 @@
 package core
 
+import "encoding/json"
+
 type MessageRole string
 
 const (
@@ -9,11 +11,87 @@ const (
 	RoleTool      MessageRole = "tool"
 )
 
+// Message is one chat turn. Multimodal messages carry Parts; Content then
+// holds their text, so text-only providers still see it. In JSON, "content"
+// is a string or, when Parts are set, an array of parts.
 type Message struct {
-	Role       MessageRole `json:"role"`
-	Content    string      `json:"content"`
-	Name       string      `json:"name,omitempty"`
-	ToolCallID string      `json:"tool_call_id,omitempty"`
+	Role       MessageRole   `json:"role"`
+	Content    string        `json:"content"`
+	Parts      []ContentPart `json:"-"`
+	Name       string        `json:"name,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
+}
+
+// Content part types.
+const (
+	ContentTypeText     = "text"
+	ContentTypeImageURL = "image_url"
+)
+
+// ContentPart is one piece of a multimodal message.
+type ContentPart struct {
+	Type     string        `json:"type"` // ContentTypeText or ContentTypeImageURL
+	Text     string        `json:"text,omitempty"`
+	ImageURL *ImageURLPart `json:"image_url,omitempty"`
+}
+
+// ImageURLPart references an image by URL or as a data:image/...;base64 URL.
+type ImageURLPart struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"` // Optional: "low", "high" or "auto"
+}
+
+type messageJSON struct {
+	Role       MessageRole     `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	Name       string          `json:"name,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+func (m Message) MarshalJSON() ([]byte, error) {
+	var content any = m.Content
+	if len(m.Parts) > 0 {
+		content = m.Parts
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(messageJSON{Role: m.Role, Content: raw, Name: m.Name, ToolCallID: m.ToolCallID})
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var mj messageJSON
+	if err := json.Unmarshal(data, &mj); err != nil {
+		return err
+	}
+	*m = Message{Role: mj.Role, Name: mj.Name, ToolCallID: mj.ToolCallID}
+	if len(mj.Content) == 0 || string(mj.Content) == "null" {
+		return nil
+	}
+	if mj.Content[0] != '[' {
+		return json.Unmarshal(mj.Content, &m.Content)
+	}
+	if err := json.Unmarshal(mj.Content, &m.Parts); err != nil {
+		return err
+	}
+	m.Content = partsText(m.Parts)
+	return nil
+}
+
+// partsText joins the text parts, one per line.
+func partsText(parts []ContentPart) string {
+	var text string
+	for _, p := range parts {
+		if p.Type != ContentTypeText || p.Text == "" {
+			continue
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += p.Text
+	}
+	return text
 }
 
 func NewSystemMessage(content string) Message {
@@ -24,6 +102,19 @@ func NewUserMessage(content string) Message {
 	return Message{Role: RoleUser, Content: content}
 }
 
+// NewUserMessageWithImage creates a user message with text followed by an
+// image, given as a URL or a data:image/...;base64 URL.
+func NewUserMessageWithImage(text, imageURL string) Message {
+	return Message{
+		Role:    RoleUser,
+		Content: text,
+		Parts: []ContentPart{
+			{Type: ContentTypeText, Text: text},
+			{Type: ContentTypeImageURL, ImageURL: &ImageURLPart{URL: imageURL}},
+		},
+	}
+}
+
 func NewAssistantMessage(content string) Message {
 	return Message{Role: RoleAssistant, Content: content}
 }
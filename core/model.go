@@ -1,11 +1,15 @@
 package core
 
 type ModelConfig struct {
-	Name        string  `json:"name"`
-	Provider    string  `json:"provider,omitempty"`
-	Temperature float64 `json:"temperature,omitempty"`
-	MaxTokens   int     `json:"max_tokens,omitempty"`
-	TopP        float64 `json:"top_p,omitempty"`
+	Name        string  `json:"name" yaml:"name"`
+	Provider    string  `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Temperature float64 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	MaxTokens   int     `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	TopP        float64 `json:"top_p,omitempty" yaml:"top_p,omitempty"`
+	// ReasoningEffort is "low", "medium" or "high" for OpenAI reasoning models (o1, o3).
+	ReasoningEffort string `json:"reasoning_effort,omitempty" yaml:"reasoning_effort,omitempty"`
+	// MaxContextTokens overrides the context window from llm.ModelContextWindow.
+	MaxContextTokens int `json:"max_context_tokens,omitempty" yaml:"max_context_tokens,omitempty"`
 }
 
 func DefaultModelConfig(name string) ModelConfig {
@@ -30,3 +34,8 @@ func (m ModelConfig) WithProvider(p string) ModelConfig {
 	m.Provider = p
 	return m
 }
+
+func (m ModelConfig) WithReasoningEffort(effort string) ModelConfig {
+	m.ReasoningEffort = effort
+	return m
+}
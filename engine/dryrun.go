@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hubenschmidt/go-fissio/config"
+	"github.com/hubenschmidt/go-fissio/core"
+	"github.com/hubenschmidt/go-fissio/llm"
+)
+
+// modelNodeTypes are the node types that call the LLM client.
+var modelNodeTypes = map[config.NodeType]bool{
+	config.NodeLLM:          true,
+	config.NodeWorker:       true,
+	config.NodeRouter:       true,
+	config.NodeOrchestrator: true,
+	config.NodeEvaluator:    true,
+	config.NodeSynthesizer:  true,
+}
+
+// DryRun checks the pipeline, including sub-pipelines, for unregistered tools
+// and models the client cannot route, without calling any model or tool.
+// input is accepted for parity with Run and is not used.
+func (e *Engine) DryRun(ctx context.Context, input string) (*DryRunReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if e.cycleErr != nil {
+		return nil, core.NewAgentError("engine.dry_run", "", e.cycleErr)
+	}
+
+	report := &DryRunReport{
+		MissingTools:       []string{},
+		UnresolvableModels: []string{},
+	}
+	report.EstimatedMaxNodes = e.dryRunPipeline(e.pipeline, report)
+
+	slices.Sort(report.MissingTools)
+	slices.Sort(report.UnresolvableModels)
+	report.MissingTools = slices.Compact(report.MissingTools)
+	report.UnresolvableModels = slices.Compact(report.UnresolvableModels)
+	return report, nil
+}
+
+// dryRunPipeline records problems in p into report and returns p's maximum node executions.
+func (e *Engine) dryRunPipeline(p *config.PipelineConfig, report *DryRunReport) int {
+	checker, _ := e.executor.client.(llm.ModelChecker)
+
+	// Loop children run inside their loop node, so count them there instead.
+	loopChildren := make(map[string]bool)
+	for _, node := range p.Nodes {
+		if node.Type == config.NodeLoop {
+			for _, target := range node.TargetNodes {
+				loopChildren[target] = true
+			}
+			loopChildren[node.ConditionNode] = true
+		}
+	}
+
+	total := 0
+	for _, node := range p.Nodes {
+		for _, name := range node.Tools {
+			if _, ok := e.executor.registry.Get(name); !ok && !slices.Contains(e.executor.optionalTools, name) {
+				report.MissingTools = append(report.MissingTools, name)
+			}
+		}
+
+		if modelNodeTypes[node.Type] {
+			model := e.executor.resolver.ResolveModelName(node)
+			if model == "" || (checker != nil && !checker.SupportsModel(model)) {
+				report.UnresolvableModels = append(report.UnresolvableModels, model)
+			}
+		}
+
+		switch {
+		case loopChildren[node.ID]:
+		case node.Type == config.NodeLoop:
+			maxIter := node.MaxIter
+			if maxIter <= 0 {
+				maxIter = 10
+			}
+			total += 1 + 2*maxIter
+		case node.Type == config.NodeSubpipeline && node.SubPipeline != nil:
+			total += 1 + e.dryRunPipeline(node.SubPipeline, report)
+		default:
+			total++
+		}
+	}
+	return total
+}
@@ -2,8 +2,12 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +16,8 @@ import (
 	"github.com/hubenschmidt/go-fissio/llm"
 	"github.com/hubenschmidt/go-fissio/monitor"
 	"github.com/hubenschmidt/go-fissio/tools"
+	"github.com/hubenschmidt/go-fissio/vector"
+	"golang.org/x/sync/errgroup"
 )
 
 type Engine struct {
@@ -20,13 +26,50 @@ type Engine struct {
 	collector monitor.MetricsCollector
 	nodeMap   map[string]*config.NodeConfig
 	edges     map[string][]string
+	rank      map[string]int
+	cycleErr  error // set when edges other than loop edges form a cycle; runs fail with it
+	logger    *slog.Logger
+	pricing   monitor.PriceTable
+	memory    *ConversationMemory
+	variables map[string]any
+	exporter  *OTELExporter
+	maxCost   float64
+
+	thinkingBudget int
+	overrideNode   string
 }
 
 type EngineConfig struct {
-	Client       llm.Client
-	Registry     *tools.Registry
-	Resolver     *ModelResolver
-	Collector    monitor.MetricsCollector
+	Client    llm.Client
+	Registry  *tools.Registry
+	Resolver  *ModelResolver
+	Collector monitor.MetricsCollector
+	Logger    *slog.Logger // Optional: defaults to slog.Default()
+
+	VectorStore vector.Store // Optional: target store for embedder nodes
+	EmbedModel  string       // Embedding model for embedder nodes (default: text-embedding-3-small)
+
+	ThinkingBudget int // Optional: per-pipeline Anthropic extended thinking budget
+
+	ModelFallbacks map[string][]string // Optional: node ID -> fallback models tried on retryable errors
+
+	ModelOverrides map[string]string // Optional: node ID -> model name used instead of the node's configured model
+
+	OptionalTools []string // Optional: tools dropped from a node's tool list, instead of failing it, when not registered
+
+	LLMCache *llm.CachedClient // Optional: replaces Client with a response-caching wrapper
+
+	Memory *ConversationMemory // Optional: prepends earlier turns to the input and records each successful run
+
+	InitialVariables map[string]any // Optional: seeds ExecutionContext.Variables, exposed to input templates as .Vars
+
+	Exporter *OTELExporter // Optional: sends each run's spans to an OTLP endpoint
+
+	// Optional: stop the run with core.ErrBudgetExceeded once the estimated
+	// cost exceeds this (0 = unlimited). Prices default to monitor.DefaultPriceTable().
+	MaxCostUSD float64
+
+	OverrideNode string // Optional: the node Replay re-executes; every other recorded node reuses its traced output
 }
 
 func NewEngine(pipeline *config.PipelineConfig, cfg EngineConfig) *Engine {
@@ -39,6 +82,14 @@ func NewEngine(pipeline *config.PipelineConfig, cfg EngineConfig) *Engine {
 	if resolver == nil {
 		resolver = NewModelResolver(core.DefaultModelConfig("gpt-4"))
 	}
+	if len(cfg.ModelOverrides) > 0 {
+		resolver = resolver.WithModelOverrides(cfg.ModelOverrides)
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 
 	nodeMap := make(map[string]*config.NodeConfig)
 	for _, n := range pipeline.Nodes {
@@ -50,22 +101,92 @@ func NewEngine(pipeline *config.PipelineConfig, cfg EngineConfig) *Engine {
 		edges[e.From.Node] = append(edges[e.From.Node], e.To.Node)
 	}
 
+	order, cycleErr := pipeline.TopologicalOrder()
+	rank := make(map[string]int, len(order))
+	for i, id := range order {
+		rank[id] = i
+	}
+
+	embedModel := cfg.EmbedModel
+	if embedModel == "" {
+		embedModel = "text-embedding-3-small"
+	}
+
+	client := cfg.Client
+	if cfg.LLMCache != nil {
+		client = cfg.LLMCache
+	}
+
+	executor := NewExecutor(client, resolver, registry)
+	executor.nodes = nodeMap
+	executor.edges = pipeline.Edges
+	executor.fallbacks = cfg.ModelFallbacks
+	executor.optionalTools = cfg.OptionalTools
+	executor.vectorStore = cfg.VectorStore
+	executor.embedModel = embedModel
+	executor.logger = logger
+
+	var pricing monitor.PriceTable
+	if cfg.MaxCostUSD > 0 {
+		pricing = monitor.DefaultPriceTable()
+	}
+
 	return &Engine{
 		pipeline:  pipeline,
-		executor:  NewExecutor(cfg.Client, resolver, registry),
+		executor:  executor,
 		collector: cfg.Collector,
 		nodeMap:   nodeMap,
 		edges:     edges,
+		rank:      rank,
+		cycleErr:  cycleErr,
+		logger:    logger,
+		memory:    cfg.Memory,
+		variables: cfg.InitialVariables,
+		exporter:  cfg.Exporter,
+		maxCost:   cfg.MaxCostUSD,
+		pricing:   pricing,
+
+		thinkingBudget: cfg.ThinkingBudget,
+		overrideNode:   cfg.OverrideNode,
 	}
 }
 
+// WithPricing enables cost estimation using the given price table.
+func (e *Engine) WithPricing(prices monitor.PriceTable) *Engine {
+	e.pricing = prices
+	return e
+}
+
+type traceIDKey struct{}
+
+// WithTraceID makes runs started with ctx use traceID instead of generating one,
+// so a caller's trace can be followed through logs, spans and stored traces.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID set by WithTraceID, or "".
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
 func (e *Engine) Run(ctx context.Context, input string) (*EngineOutput, error) {
 	start := time.Now()
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		traceID = fmt.Sprintf("trace_%d", start.UnixNano())
+	}
+
+	if e.thinkingBudget > 0 {
+		ctx = llm.WithThinkingBudget(ctx, e.thinkingBudget)
+	}
 
-	log.Println("╔══════════════════════════════════════════════════════════════")
-	log.Printf("║ PIPELINE: %s", e.pipeline.Name)
-	log.Printf("║ Input: %.50s...", input)
-	log.Println("╠══════════════════════════════════════════════════════════════")
+	e.logger.DebugContext(ctx, "pipeline_start",
+		slog.String("trace_id", traceID),
+		slog.String("pipeline", e.pipeline.Name),
+		slog.Int("input_chars", len(input)),
+	)
 
 	entryNode := e.pipeline.EntryNode
 	if entryNode == "" {
@@ -75,14 +196,26 @@ func (e *Engine) Run(ctx context.Context, input string) (*EngineOutput, error) {
 	if entryNode == "" {
 		return nil, core.NewAgentError("engine.run", "", core.ErrNodeNotFound)
 	}
+	if e.cycleErr != nil {
+		return nil, core.NewAgentError("engine.run", "", e.cycleErr)
+	}
+
+	entryInput := input
+	if e.memory != nil {
+		entryInput = e.memory.prependTo(input)
+	}
 
-	execCtx := NewExecutionContext(NodeInput{Content: input})
+	execCtx := NewExecutionContext(NodeInput{TraceID: traceID, Content: entryInput})
+	maps.Copy(execCtx.Variables, e.variables)
+	ctx = withVariables(ctx, execCtx.Variables)
 	outputs := make(map[string]NodeOutput)
 	var spans []Span
+	var cost float64
 	step := 0
 
 	currentNodes := []string{entryNode}
 	visited := make(map[string]bool)
+	fannedOut := make(map[string]bool)
 
 	for len(currentNodes) > 0 {
 		var nextNodes []string
@@ -90,48 +223,82 @@ func (e *Engine) Run(ctx context.Context, input string) (*EngineOutput, error) {
 		unvisitedNodes := filterNodes(currentNodes, func(id string) bool {
 			return !visited[id] && e.nodeMap[id] != nil
 		})
+		e.sortByRank(unvisitedNodes)
+		// A node reached from several sources in the same layer runs once.
+		unvisitedNodes = slices.Compact(unvisitedNodes)
+
+		// Branches of a fan-out node run concurrently up front; their results
+		// are then recorded in rank order like any other node.
+		concurrent := filterNodes(unvisitedNodes, func(id string) bool {
+			return fannedOut[id] && e.nodeMap[id].Type != config.NodeJoin
+		})
+		runs := e.runConcurrently(ctx, concurrent, execCtx, &step)
 
 		for _, nodeID := range unvisitedNodes {
-			visited[nodeID] = true
 			node := e.nodeMap[nodeID]
-
-			step++
-			model := node.Model.Name
-			if model == "" {
-				model = "default"
-			}
-			log.Println("╠──────────────────────────────────────────────────────────────")
-			log.Printf("║ [%d] NODE: %s (%s)", step, nodeID, node.Type)
-			log.Printf("║     Model: %s", model)
-			if len(node.Tools) > 0 {
-				log.Printf("║     Tools: %v", node.Tools)
+			if node.Type == config.NodeJoin && !e.sourcesComplete(nodeID, execCtx) {
+				continue
 			}
+			visited[nodeID] = true
 
-			nodeInput := e.buildNodeInput(nodeID, execCtx)
-			nodeStart := time.Now()
-			output, err := e.executor.Execute(ctx, node, nodeInput)
-			nodeEnd := time.Now()
+			run, ok := runs[nodeID]
+			if !ok {
+				step++
+				run = e.runNode(ctx, step, node, e.buildNodeInput(nodeID, execCtx))
+			}
+			nodeInput, output, err := run.input, run.output, run.err
+			nodeStart, nodeEnd := run.start, run.end
 
 			if err != nil {
-				log.Printf("║     ✗ Error: %v", err)
-				log.Println("╚══════════════════════════════════════════════════════════════")
+				e.logger.ErrorContext(ctx, "node_failed",
+					slog.String("node_id", nodeID),
+					slog.Any("error", err),
+				)
+				if errors.Is(err, core.ErrTimeout) {
+					step++
+					spans = append(spans, Span{
+						SpanID:    fmt.Sprintf("span_%d", step),
+						NodeID:    nodeID,
+						NodeType:  node.Type.String(),
+						StartTime: nodeStart.UnixMilli(),
+						EndTime:   nodeEnd.UnixMilli(),
+						Input:     nodeInput.Content,
+						Duration:  nodeEnd.Sub(nodeStart),
+						Metadata:  map[string]any{"timed_out": true},
+					})
+				}
+				e.exportSpans(ctx, spans, traceID)
 				return &EngineOutput{
-					Success:  false,
-					Error:    err,
-					Outputs:  outputs,
-					Spans:    spans,
-					Duration: time.Since(start),
+					TraceID:          traceID,
+					Success:          false,
+					Error:            err,
+					Outputs:          outputs,
+					Spans:            spans,
+					Duration:         time.Since(start),
+					EstimatedCostUSD: cost,
 				}, err
 			}
 
-			log.Printf("║     ✓ Completed in %v", nodeEnd.Sub(nodeStart))
-			log.Printf("║     ← Response: %d chars, %d/%d tokens", len(output.Content), output.TokensIn, output.TokensOut)
+			e.logger.InfoContext(ctx, "node_complete",
+				slog.String("node_id", nodeID),
+				slog.Duration("duration", nodeEnd.Sub(nodeStart)),
+				slog.Int("output_chars", len(output.Content)),
+				slog.Int("tokens_in", output.TokensIn),
+				slog.Int("tokens_out", output.TokensOut),
+			)
+
+			emitEvent(ctx, EngineEvent{
+				Type:     EventNodeCompleted,
+				NodeID:   nodeID,
+				NodeType: node.Type.String(),
+				Content:  output.Content,
+			})
 
 			step++
 			spans = append(spans, Span{
 				SpanID:       fmt.Sprintf("span_%d", step),
 				NodeID:       nodeID,
-				NodeType:     string(node.Type),
+				NodeType:     node.Type.String(),
 				StartTime:    nodeStart.UnixMilli(),
 				EndTime:      nodeEnd.UnixMilli(),
 				Input:        nodeInput.Content,
@@ -139,34 +306,172 @@ func (e *Engine) Run(ctx context.Context, input string) (*EngineOutput, error) {
 				InputTokens:  output.TokensIn,
 				OutputTokens: output.TokensOut,
 				Duration:     nodeEnd.Sub(nodeStart),
+				Children:     output.Spans,
 			})
 
+			// Parallel and loop targets run inside their owning node; never schedule them again.
+			if node.Type == config.NodeParallel || node.Type == config.NodeLoop {
+				for _, target := range node.TargetNodes {
+					visited[target] = true
+				}
+			}
+			if node.Type == config.NodeLoop {
+				visited[node.ConditionNode] = true
+			}
+
+			modelName := e.executor.resolver.ResolveModelName(node)
+			var nodeCost float64
+			if e.pricing != nil {
+				nodeCost = e.pricing.Cost(modelName, output.TokensIn, output.TokensOut)
+				cost += nodeCost
+			}
+
+			// A parallel node's targets are recorded as if they had run on
+			// their own, so their successors and joins can see them.
+			for _, branch := range output.branches {
+				outputs[branch.NodeID] = branch
+				execCtx.AddOutput(branch)
+			}
 			outputs[nodeID] = output
 			execCtx.AddOutput(output)
-			e.recordMetrics(nodeID, output)
+			for k, v := range output.Metadata {
+				execCtx.SetVar(k, v)
+			}
+			notifyNodeOutput(ctx, output)
+			e.recordMetrics(nodeID, modelName, nodeCost, output)
+
+			if e.maxCost > 0 && cost > e.maxCost {
+				e.logger.WarnContext(ctx, "budget_exceeded",
+					slog.String("node_id", nodeID),
+					slog.Float64("cost_usd", cost),
+					slog.Float64("max_cost_usd", e.maxCost),
+				)
+				err := core.WithContext(core.NewAgentError("engine.run", nodeID, core.ErrBudgetExceeded), "cost_usd", cost)
+				e.exportSpans(ctx, spans, traceID)
+				return &EngineOutput{
+					TraceID:          traceID,
+					Success:          false,
+					FinalNode:        nodeID,
+					Content:          output.Content,
+					Error:            err,
+					Outputs:          outputs,
+					Spans:            spans,
+					Duration:         time.Since(start),
+					EstimatedCostUSD: cost,
+				}, err
+			}
 
-			nextNodes = append(nextNodes, e.getNextNodes(nodeID, output)...)
+			next := e.getNextNodes(nodeID, output)
+			for _, branch := range output.branches {
+				next = append(next, e.getNextNodes(branch.NodeID, branch)...)
+			}
+			if node.Type == config.NodeFanOut || node.Type == config.NodeSplitter {
+				for _, id := range next {
+					fannedOut[id] = true
+				}
+			}
+			nextNodes = append(nextNodes, next...)
 		}
 
 		currentNodes = nextNodes
 	}
 
 	finalOutput := e.findFinalOutput(execCtx)
-	log.Println("╠══════════════════════════════════════════════════════════════")
-	log.Printf("║ Pipeline complete in %v", time.Since(start))
-	log.Printf("║ Output: %d chars", len(finalOutput.Content))
-	log.Println("╚══════════════════════════════════════════════════════════════")
+	e.logger.InfoContext(ctx, "pipeline_complete",
+		slog.String("pipeline", e.pipeline.Name),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("output_chars", len(finalOutput.Content)),
+	)
+
+	if e.memory != nil {
+		e.memory.Add(string(core.RoleUser), input)
+		e.memory.Add(string(core.RoleAssistant), finalOutput.Content)
+	}
+	e.exportSpans(ctx, spans, traceID)
 
 	return &EngineOutput{
-		Success:   true,
-		FinalNode: finalOutput.NodeID,
-		Content:   finalOutput.Content,
-		Outputs:   outputs,
-		Spans:     spans,
-		Duration:  time.Since(start),
+		TraceID:          traceID,
+		Success:          true,
+		FinalNode:        finalOutput.NodeID,
+		Content:          finalOutput.Content,
+		Outputs:          outputs,
+		Spans:            spans,
+		Duration:         time.Since(start),
+		EstimatedCostUSD: cost,
 	}, nil
 }
 
+// nodeRun is the result of executing one node.
+type nodeRun struct {
+	input      NodeInput
+	output     NodeOutput
+	err        error
+	start, end time.Time
+}
+
+func (e *Engine) runNode(ctx context.Context, step int, node *config.NodeConfig, input NodeInput) nodeRun {
+	model := node.Model.Name
+	if model == "" {
+		model = "default"
+	}
+	e.logger.DebugContext(ctx, "node_start",
+		slog.Int("step", step),
+		slog.String("node_id", node.ID),
+		slog.String("node_type", node.Type.String()),
+		slog.String("model", model),
+		slog.Any("tools", node.Tools),
+	)
+
+	emitEvent(ctx, EngineEvent{Type: EventNodeStarted, NodeID: node.ID, NodeType: node.Type.String()})
+	run := nodeRun{input: input, start: time.Now()}
+	if recorded, ok := replayedOutput(ctx, node.ID); ok {
+		run.output = recorded
+	} else {
+		run.output, run.err = e.executor.Execute(ctx, node, input)
+	}
+	run.end = time.Now()
+	return run
+}
+
+// runConcurrently executes the given nodes in parallel. Siblings are not
+// cancelled on failure, so each run keeps its own error for the caller to report.
+func (e *Engine) runConcurrently(ctx context.Context, ids []string, execCtx *ExecutionContext, step *int) map[string]nodeRun {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	runs := make([]nodeRun, len(ids))
+	var g errgroup.Group
+	for i, id := range ids {
+		*step++
+		node, input, nodeStep := e.nodeMap[id], e.buildNodeInput(id, execCtx), *step
+		g.Go(func() error {
+			runs[i] = e.runNode(ctx, nodeStep, node, input)
+			return runs[i].err
+		})
+	}
+	g.Wait()
+
+	byID := make(map[string]nodeRun, len(ids))
+	for i, id := range ids {
+		byID[id] = runs[i]
+	}
+	return byID
+}
+
+// exportSpans sends spans to the configured exporter; failures are logged, not returned.
+func (e *Engine) exportSpans(ctx context.Context, spans []Span, traceID string) {
+	if e.exporter == nil {
+		return
+	}
+	if err := e.exporter.Export(withPipelineID(ctx, e.pipeline.ID), spans, traceID); err != nil {
+		e.logger.WarnContext(ctx, "span_export_failed",
+			slog.String("trace_id", traceID),
+			slog.Any("error", err),
+		)
+	}
+}
+
 func (e *Engine) findEntryNode() string {
 	hasIncoming := make(map[string]bool)
 	for _, edge := range e.pipeline.Edges {
@@ -187,16 +492,20 @@ func (e *Engine) findEntryNode() string {
 
 func (e *Engine) buildNodeInput(nodeID string, ctx *ExecutionContext) NodeInput {
 	sources := e.findSourceNodes(nodeID)
-	content := e.buildContentFromSources(sources, ctx)
+	parts := e.sourceOutputs(sources, ctx)
+	content := strings.Join(parts, "\n\n")
 
 	if content == "" {
 		content = ctx.Input.Content
 	}
 
 	return NodeInput{
-		NodeID:  nodeID,
-		Content: content,
-		Sources: sources,
+		NodeID:   nodeID,
+		TraceID:  ctx.Input.TraceID,
+		Content:  content,
+		Metadata: maps.Clone(ctx.Variables),
+		Sources:  sources,
+		Parts:    parts,
 	}
 }
 
@@ -209,17 +518,35 @@ func (e *Engine) findSourceNodes(nodeID string) []string {
 			}
 		}
 	}
+	e.sortByRank(sources)
 	return sources
 }
 
-func (e *Engine) buildContentFromSources(sources []string, ctx *ExecutionContext) string {
+// sortByRank orders node IDs by their position in the pipeline's topological order.
+func (e *Engine) sortByRank(ids []string) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		return e.rank[ids[i]] < e.rank[ids[j]]
+	})
+}
+
+// sourcesComplete reports whether every node with an edge into nodeID has produced output.
+func (e *Engine) sourcesComplete(nodeID string, ctx *ExecutionContext) bool {
+	for _, src := range e.findSourceNodes(nodeID) {
+		if _, ok := ctx.GetOutput(src); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Engine) sourceOutputs(sources []string, ctx *ExecutionContext) []string {
 	var parts []string
 	for _, from := range sources {
 		if out, ok := ctx.GetOutput(from); ok {
 			parts = append(parts, out.Content)
 		}
 	}
-	return strings.Join(parts, "\n\n")
+	return parts
 }
 
 func filterNodes(nodes []string, predicate func(string) bool) []string {
@@ -232,8 +559,10 @@ func filterNodes(nodes []string, predicate func(string) bool) []string {
 	return result
 }
 
+// getNextNodes returns the nodes to run after nodeID. A router's output
+// always decides, even when it names no successor.
 func (e *Engine) getNextNodes(nodeID string, output NodeOutput) []string {
-	if len(output.NextNodes) > 0 {
+	if len(output.NextNodes) > 0 || e.nodeMap[nodeID].Type == config.NodeRouter {
 		return output.NextNodes
 	}
 	return e.edges[nodeID]
@@ -246,7 +575,7 @@ func (e *Engine) findFinalOutput(ctx *ExecutionContext) NodeOutput {
 	return ctx.History[len(ctx.History)-1]
 }
 
-func (e *Engine) recordMetrics(nodeID string, output NodeOutput) {
+func (e *Engine) recordMetrics(nodeID, model string, cost float64, output NodeOutput) {
 	if e.collector == nil {
 		return
 	}
@@ -256,5 +585,7 @@ func (e *Engine) recordMetrics(nodeID string, output NodeOutput) {
 		TokensOut: output.TokensOut,
 		Duration:  output.Duration,
 		Success:   true,
+		Model:     model,
+		CostUSD:   cost,
 	})
 }
@@ -2,19 +2,39 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/hubenschmidt/go-fissio/config"
 	"github.com/hubenschmidt/go-fissio/core"
 	"github.com/hubenschmidt/go-fissio/llm"
 	"github.com/hubenschmidt/go-fissio/tools"
+	"github.com/hubenschmidt/go-fissio/vector"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type Executor struct {
 	client   llm.Client
 	resolver *ModelResolver
 	registry *tools.Registry
+	nodes    map[string]*config.NodeConfig
+	edges    []config.EdgeConfig
+
+	fallbacks     map[string][]string
+	optionalTools []string
+	vectorStore   vector.Store
+	embedModel    string
+	logger        *slog.Logger
 }
 
 func NewExecutor(client llm.Client, resolver *ModelResolver, registry *tools.Registry) *Executor {
@@ -38,6 +58,13 @@ func (e *Executor) Execute(ctx context.Context, node *config.NodeConfig, input N
 		config.NodeEvaluator:    e.executeEvaluator,
 		config.NodeSynthesizer:  e.executeSynthesizer,
 		config.NodeCoordinator:  e.executeCoordinator,
+		config.NodeParallel:     e.executeParallel,
+		config.NodeJoin:         e.executeJoin,
+		config.NodeEmbedder:     e.executeEmbedder,
+		config.NodeSubpipeline:  e.executeSubpipeline,
+		config.NodeLoop:         e.executeLoop,
+		config.NodeFanOut:       e.executeFanOut,
+		config.NodeSplitter:     e.executeSplitter,
 	}
 
 	handler, ok := handlers[node.Type]
@@ -45,25 +72,174 @@ func (e *Executor) Execute(ctx context.Context, node *config.NodeConfig, input N
 		return NodeOutput{}, core.NewAgentError("executor.execute", node.ID, fmt.Errorf("unknown node type: %s", node.Type))
 	}
 
+	if node.InputTemplate != "" {
+		var err error
+		if input, err = renderInputTemplate(ctx, node, input); err != nil {
+			return NodeOutput{}, err
+		}
+	}
+
+	if limit := e.inputTokenLimit(node); limit > 0 {
+		input = e.truncateInput(ctx, node, input, limit)
+	}
+
+	if node.Model.ReasoningEffort != "" {
+		ctx = llm.WithReasoningEffort(ctx, node.Model.ReasoningEffort)
+	}
+
+	timeout := node.Timeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	output, err := handler(ctx, node, input)
 	if err != nil {
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return NodeOutput{}, core.WithContext(
+				core.NewAgentError("executor.timeout", node.ID, core.ErrTimeout),
+				"timeout_secs", int(timeout/time.Second),
+			)
+		}
 		return NodeOutput{}, err
 	}
 
+	if node.OutputParser != nil {
+		fields, err := parseOutput(node.OutputParser, output.Content)
+		if err != nil {
+			return NodeOutput{}, core.NewAgentError("executor.parse", node.ID, err)
+		}
+		if len(fields) > 0 && output.Metadata == nil {
+			output.Metadata = make(map[string]any, len(fields))
+		}
+		maps.Copy(output.Metadata, fields)
+	}
+
+	if node.OutputTransform != "" {
+		if output, err = applyOutputTransform(ctx, node, output); err != nil {
+			return NodeOutput{}, err
+		}
+	}
+
 	output.NodeID = node.ID
 	output.Duration = time.Since(start)
 	return output, nil
 }
 
+// availableTools returns the node's tools minus any optional tools that are
+// not registered, so a missing optional tool does not fail the node.
+func (e *Executor) availableTools(ctx context.Context, node *config.NodeConfig) []string {
+	names := make([]string, 0, len(node.Tools))
+	for _, name := range node.Tools {
+		if _, ok := e.registry.Get(name); !ok && slices.Contains(e.optionalTools, name) {
+			e.logger.DebugContext(ctx, "optional_tool_unavailable",
+				slog.String("node_id", node.ID),
+				slog.String("tool", name),
+			)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// chat calls the node's model, failing over to any fallbacks configured for the node.
+func (e *Executor) chat(ctx context.Context, node *config.NodeConfig, model, system, user string) (*llm.LLMResponse, error) {
+	fallbacks := e.fallbacks[node.ID]
+	if len(fallbacks) == 0 {
+		return e.client.Chat(ctx, model, system, user)
+	}
+
+	resp, used, err := llm.ChatWithFallback(ctx, e.client, llm.FallbackConfig{Primary: model, Fallbacks: fallbacks}, system, user)
+	if err != nil {
+		return nil, err
+	}
+	if used != model {
+		e.logger.WarnContext(ctx, "model_fallback",
+			slog.String("node_id", node.ID),
+			slog.String("primary", model),
+			slog.String("used", used),
+		)
+	}
+	return resp, nil
+}
+
 func (e *Executor) executeLLM(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
-	resp, err := e.client.Chat(ctx, model, node.Prompt, input.Content)
+	if node.ResponseSchema != nil {
+		return e.executeStructured(ctx, node, model, input)
+	}
+
+	if sc, ok := e.client.(llm.StreamClient); ok && streaming(ctx) && len(e.fallbacks[node.ID]) == 0 {
+		return e.executeLLMStream(ctx, sc, node, model, input)
+	}
+
+	resp, err := e.chat(ctx, node, model, node.SystemInstruction(), input.Content)
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, err)
+	}
+
+	return NodeOutput{
+		Content:   resp.Content,
+		TokensIn:  resp.Usage.PromptTokens,
+		TokensOut: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// executeLLMStream streams the response, emitting a TokenChunk event per chunk.
+func (e *Executor) executeLLMStream(ctx context.Context, sc llm.StreamClient, node *config.NodeConfig, model string, input NodeInput) (NodeOutput, error) {
+	stream, err := sc.ChatStreamWithMessages(ctx, model, node.SystemInstruction(), []llm.Message{{Role: "user", Content: input.Content}})
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, err)
+	}
+
+	var content strings.Builder
+	var usage llm.Usage
+	for chunk := range stream {
+		if chunk.Error != nil {
+			return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, chunk.Error)
+		}
+		if chunk.Content != "" {
+			content.WriteString(chunk.Content)
+			emitEvent(ctx, EngineEvent{Type: EventTokenChunk, NodeID: node.ID, NodeType: node.Type.String(), Content: chunk.Content})
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, err)
+	}
+
+	return NodeOutput{
+		Content:   content.String(),
+		TokensIn:  usage.PromptTokens,
+		TokensOut: usage.CompletionTokens,
+	}, nil
+}
+
+// executeStructured requests schema-constrained output and exposes the parsed
+// JSON to downstream nodes as Metadata["structured"].
+func (e *Executor) executeStructured(ctx context.Context, node *config.NodeConfig, model string, input NodeInput) (NodeOutput, error) {
+	sc, ok := e.client.(llm.StructuredClient)
+	if !ok {
+		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, fmt.Errorf("client does not support structured output"))
+	}
+
+	resp, err := sc.ChatWithStructuredOutput(ctx, model, node.SystemInstruction(), input.Content, node.ResponseSchema)
 	if err != nil {
 		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, err)
 	}
 
+	var structured any
+	if err := json.Unmarshal([]byte(resp.Content), &structured); err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.llm", node.ID, fmt.Errorf("parse structured output: %w", err))
+	}
+
 	return NodeOutput{
 		Content:   resp.Content,
+		Metadata:  map[string]any{"structured": structured},
 		TokensIn:  resp.Usage.PromptTokens,
 		TokensOut: resp.Usage.CompletionTokens,
 	}, nil
@@ -72,7 +248,11 @@ func (e *Executor) executeLLM(ctx context.Context, node *config.NodeConfig, inpu
 func (e *Executor) executeWorker(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
 
-	nodeTools, err := e.registry.GetMultiple(node.Tools)
+	names := e.availableTools(ctx, node)
+	if len(names) == 0 && len(node.Tools) > 0 {
+		return e.executeLLM(ctx, node, input)
+	}
+	nodeTools, err := e.registry.GetMultiple(names)
 	if err != nil {
 		return NodeOutput{}, err
 	}
@@ -80,6 +260,8 @@ func (e *Executor) executeWorker(ctx context.Context, node *config.NodeConfig, i
 
 	msgs := []core.Message{core.NewUserMessage(input.Content)}
 	var totalIn, totalOut int
+	var spans []Span
+	var steps []map[string]any
 
 	maxIter := node.MaxIter
 	if maxIter <= 0 {
@@ -87,24 +269,50 @@ func (e *Executor) executeWorker(ctx context.Context, node *config.NodeConfig, i
 	}
 
 	for i := 0; i < maxIter; i++ {
-		resp, err := e.client.ChatWithTools(ctx, model, node.Prompt, msgs, schemas, nil)
+		iterStart := time.Now()
+		resp, err := e.client.ChatWithTools(ctx, model, node.SystemInstruction(), msgs, schemas, nil)
 		if err != nil {
 			return NodeOutput{}, core.NewAgentError("executor.worker", node.ID, err)
 		}
+		iterEnd := time.Now()
 
 		totalIn += resp.Usage.PromptTokens
 		totalOut += resp.Usage.CompletionTokens
 
+		span := Span{
+			SpanID:         fmt.Sprintf("%s_iter_%d", node.ID, i+1),
+			NodeID:         node.ID,
+			NodeType:       node.Type.String(),
+			StartTime:      iterStart.UnixMilli(),
+			EndTime:        iterEnd.UnixMilli(),
+			Output:         resp.Content,
+			InputTokens:    resp.Usage.PromptTokens,
+			OutputTokens:   resp.Usage.CompletionTokens,
+			ToolCallCount:  len(resp.ToolCalls),
+			IterationCount: i + 1,
+			Duration:       iterEnd.Sub(iterStart),
+		}
+		if step := reactStep(resp.Content, resp.ToolCalls); step != nil {
+			span.Metadata = step
+			steps = append(steps, step)
+		}
+		spans = append(spans, span)
+
 		if !resp.HasToolCalls() {
-			return NodeOutput{
+			out := NodeOutput{
 				Content:   resp.Content,
 				TokensIn:  totalIn,
 				TokensOut: totalOut,
-			}, nil
+				Spans:     spans,
+			}
+			if len(steps) > 0 {
+				out.Metadata = map[string]any{"react_steps": steps}
+			}
+			return out, nil
 		}
 
 		msgs = append(msgs, core.NewAssistantMessage(resp.Content))
-		toolResults := e.executeToolCalls(ctx, resp.ToolCalls, nodeTools)
+		toolResults := e.executeToolCalls(ctx, resp.ToolCalls, nodeTools, node.ToolConcurrency)
 
 		for _, tr := range toolResults {
 			msgs = append(msgs, core.NewToolMessage(tr.ToolCallID, tr.Content))
@@ -114,16 +322,42 @@ func (e *Executor) executeWorker(ctx context.Context, node *config.NodeConfig, i
 	return NodeOutput{}, core.NewAgentError("executor.worker", node.ID, core.ErrMaxIterations)
 }
 
-func (e *Executor) executeToolCalls(ctx context.Context, calls []core.ToolCall, nodeTools []tools.Tool) []core.ToolResult {
+// executeToolCalls runs calls one at a time, or up to concurrency at once when
+// concurrency > 1. In parallel mode a failed call cancels the calls still
+// running or waiting. Results are in the order of calls either way.
+func (e *Executor) executeToolCalls(ctx context.Context, calls []core.ToolCall, nodeTools []tools.Tool, concurrency int) []core.ToolResult {
 	toolMap := make(map[string]tools.Tool)
 	for _, t := range nodeTools {
 		toolMap[t.Name()] = t
 	}
 
 	results := make([]core.ToolResult, len(calls))
+	if concurrency <= 1 || len(calls) <= 1 {
+		for i, call := range calls {
+			results[i] = e.executeSingleToolCall(ctx, call, toolMap)
+		}
+		return results
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var g errgroup.Group
+	g.SetLimit(concurrency)
 	for i, call := range calls {
-		results[i] = e.executeSingleToolCall(ctx, call, toolMap)
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				results[i] = core.NewToolError(call.ID, err.Error())
+				return nil
+			}
+			results[i] = e.executeSingleToolCall(ctx, call, toolMap)
+			if results[i].IsError {
+				cancel()
+			}
+			return nil
+		})
 	}
+	g.Wait()
 
 	return results
 }
@@ -143,34 +377,139 @@ func (e *Executor) executeSingleToolCall(ctx context.Context, call core.ToolCall
 
 func (e *Executor) executeRouter(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
-	prompt := node.Prompt + "\n\nAvailable routes: " + fmt.Sprintf("%v", node.NextNodes)
+	prompt := node.SystemInstruction() + "\n\nAvailable routes: " + fmt.Sprintf("%v", e.availableRoutes(node)) +
+		"\n\nRespond with a JSON object of the form {\"route\": \"<route>\"}."
 
-	resp, err := e.client.Chat(ctx, model, prompt, input.Content)
+	resp, err := e.chat(llm.WithJSONMode(ctx), node, model, prompt, input.Content)
 	if err != nil {
 		return NodeOutput{}, core.NewAgentError("executor.router", node.ID, err)
 	}
 
+	route := parseRoute(resp.Content)
+	next, err := e.matchRoute(node, route)
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.router", node.ID, err)
+	}
+
+	// Extra fields in the router's JSON (e.g. "language") become run variables.
+	metadata := make(map[string]any)
+	json.Unmarshal([]byte(strings.TrimSpace(resp.Content)), &metadata)
+	metadata["route"] = route
+
+	// The router only classifies; downstream nodes receive the original input.
 	return NodeOutput{
-		Content:   resp.Content,
-		NextNodes: []string{resp.Content},
+		Content:   input.Content,
+		NextNodes: next,
+		Metadata:  metadata,
 		TokensIn:  resp.Usage.PromptTokens,
 		TokensOut: resp.Usage.CompletionTokens,
 	}, nil
 }
 
+// availableRoutes lists the route names a router may choose from.
+func (e *Executor) availableRoutes(node *config.NodeConfig) []string {
+	var routes []string
+	for _, edge := range e.outgoingEdges(node.ID) {
+		if edge.Type == config.EdgeConditional && !strings.HasPrefix(edge.Condition, "~") {
+			routes = append(routes, edgeCondition(edge))
+		}
+	}
+	if len(routes) == 0 {
+		return node.NextNodes
+	}
+	return routes
+}
+
+// matchRoute evaluates the router's outgoing edges against route. Conditional
+// edges are taken when their condition equals route, or matches it as a regular
+// expression when prefixed with "~". Non-conditional edges are always taken.
+// Without any conditional edges the route itself names the next node; with
+// some, a route matching none of them is an error.
+func (e *Executor) matchRoute(node *config.NodeConfig, route string) ([]string, error) {
+	edges := e.outgoingEdges(node.ID)
+	hasConditional, matchedAny := false, false
+	var next []string
+
+	for _, edge := range edges {
+		if edge.Type != config.EdgeConditional {
+			next = append(next, edge.To.Node)
+			continue
+		}
+		hasConditional = true
+		matched, err := matchCondition(edgeCondition(edge), route)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", core.ErrInvalidEdge, err)
+		}
+		if matched {
+			matchedAny = true
+			next = append(next, edge.To.Node)
+		}
+	}
+
+	if !hasConditional {
+		return []string{route}, nil
+	}
+	if !matchedAny {
+		return nil, fmt.Errorf("%w: %q", core.ErrNoRoute, route)
+	}
+	return next, nil
+}
+
+func (e *Executor) outgoingEdges(nodeID string) []config.EdgeConfig {
+	var result []config.EdgeConfig
+	for _, edge := range e.edges {
+		if edge.From.Node == nodeID {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// edgeCondition returns the edge's condition, defaulting to the target node ID.
+func edgeCondition(edge config.EdgeConfig) string {
+	if edge.Condition == "" {
+		return edge.To.Node
+	}
+	return edge.Condition
+}
+
+func matchCondition(condition, route string) (bool, error) {
+	if pattern, ok := strings.CutPrefix(condition, "~"); ok {
+		return regexp.MatchString(pattern, route)
+	}
+	return condition == route, nil
+}
+
+// parseRoute extracts the "route" field from a router's JSON response,
+// falling back to the trimmed raw content.
+func parseRoute(content string) string {
+	var out struct {
+		Route string `json:"route"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &out); err == nil && out.Route != "" {
+		return out.Route
+	}
+	return strings.TrimSpace(content)
+}
+
 func (e *Executor) executeGate(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	return NodeOutput{Content: input.Content}, nil
 }
 
+// executeAggregator combines its upstream outputs, separated by node.Delimiter
+// when one is set. An input template takes precedence over the delimiter.
 func (e *Executor) executeAggregator(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
-	return NodeOutput{Content: input.Content}, nil
+	if node.Delimiter == "" || node.InputTemplate != "" || len(input.Parts) == 0 {
+		return NodeOutput{Content: input.Content}, nil
+	}
+	return NodeOutput{Content: strings.Join(input.Parts, node.Delimiter)}, nil
 }
 
 func (e *Executor) executeOrchestrator(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
-	prompt := node.Prompt + "\n\nTarget nodes: " + fmt.Sprintf("%v", node.TargetNodes)
+	prompt := node.SystemInstruction() + "\n\nTarget nodes: " + fmt.Sprintf("%v", node.TargetNodes)
 
-	resp, err := e.client.Chat(ctx, model, prompt, input.Content)
+	resp, err := e.chat(ctx, node, model, prompt, input.Content)
 	if err != nil {
 		return NodeOutput{}, core.NewAgentError("executor.orchestrator", node.ID, err)
 	}
@@ -185,7 +524,7 @@ func (e *Executor) executeOrchestrator(ctx context.Context, node *config.NodeCon
 
 func (e *Executor) executeEvaluator(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
-	resp, err := e.client.Chat(ctx, model, node.Prompt, input.Content)
+	resp, err := e.chat(ctx, node, model, node.SystemInstruction(), input.Content)
 	if err != nil {
 		return NodeOutput{}, core.NewAgentError("executor.evaluator", node.ID, err)
 	}
@@ -199,7 +538,7 @@ func (e *Executor) executeEvaluator(ctx context.Context, node *config.NodeConfig
 
 func (e *Executor) executeSynthesizer(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
 	model := e.resolver.ResolveModelName(node)
-	resp, err := e.client.Chat(ctx, model, node.Prompt, input.Content)
+	resp, err := e.chat(ctx, node, model, node.SystemInstruction(), input.Content)
 	if err != nil {
 		return NodeOutput{}, core.NewAgentError("executor.synthesizer", node.ID, err)
 	}
@@ -217,3 +556,274 @@ func (e *Executor) executeCoordinator(ctx context.Context, node *config.NodeConf
 		NextNodes: node.TargetNodes,
 	}, nil
 }
+
+// executeParallel runs every target node concurrently against the same input
+// and waits for all of them before returning their combined output.
+func (e *Executor) executeParallel(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	targets := make([]*config.NodeConfig, 0, len(node.TargetNodes))
+	for _, id := range node.TargetNodes {
+		target, ok := e.nodes[id]
+		if !ok {
+			return NodeOutput{}, core.NewAgentError("executor.parallel", node.ID, fmt.Errorf("%w: %s", core.ErrNodeNotFound, id))
+		}
+		targets = append(targets, target)
+	}
+
+	outputs := make([]NodeOutput, len(targets))
+	errs := make([]error, len(targets))
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], errs[i] = e.Execute(ctx, target, NodeInput{
+				NodeID:  target.ID,
+				TraceID: input.TraceID,
+				Content: input.Content,
+				Sources: []string{node.ID},
+			})
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.parallel", node.ID, err)
+	}
+
+	parts := make([]string, len(outputs))
+	branches := make(map[string]any, len(outputs))
+	var totalIn, totalOut int
+	for i, out := range outputs {
+		parts[i] = out.Content
+		branches[out.NodeID] = out.Content
+		totalIn += out.TokensIn
+		totalOut += out.TokensOut
+	}
+
+	return NodeOutput{
+		Content:   strings.Join(parts, "\n\n"),
+		Metadata:  map[string]any{"branches": branches},
+		TokensIn:  totalIn,
+		TokensOut: totalOut,
+		branches:  outputs,
+	}, nil
+}
+
+// executeFanOut broadcasts its input to its targets (or outgoing edges when
+// none are set); the engine runs them concurrently.
+func (e *Executor) executeFanOut(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	return NodeOutput{Content: input.Content, NextNodes: node.TargetNodes}, nil
+}
+
+// executeSplitter splits its input into chunks of at most node.ChunkSize
+// characters, exposed to its targets as the "chunks" variable, and sends one
+// chunk to each target; the engine runs them concurrently. When there are more
+// chunks than targets the chunks grow so every target's share fits.
+func (e *Executor) executeSplitter(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	size := node.ChunkSize
+	if size <= 0 {
+		size = 2000
+	}
+	chunks := (&vector.RecursiveTextSplitter{ChunkSize: size}).Split(input.Content)
+	if len(chunks) == 0 {
+		chunks = []string{input.Content}
+	}
+
+	targets := node.TargetNodes
+	if n := len(targets); n > 0 && len(chunks) > n {
+		size = max(size, (utf8.RuneCountInString(input.Content)+n-1)/n)
+		chunks = (&vector.RecursiveTextSplitter{ChunkSize: size}).Split(input.Content)
+		// Separator boundaries can still leave extra chunks; the last target takes them.
+		if len(chunks) > n {
+			chunks = append(chunks[:n-1], strings.Join(chunks[n-1:], "\n"))
+		}
+	}
+	if len(targets) > len(chunks) {
+		targets = targets[:len(chunks)]
+	}
+
+	return NodeOutput{
+		Content:   input.Content,
+		NextNodes: targets,
+		Metadata:  map[string]any{"chunks": chunks, "chunk_count": len(chunks)},
+	}, nil
+}
+
+// executeJoin combines the outputs of all upstream nodes, concatenated or,
+// with JoinLLMMerge, synthesized by the node's model. The engine only
+// schedules a join once every source node has completed.
+func (e *Executor) executeJoin(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	if node.JoinStrategy != config.JoinLLMMerge {
+		return NodeOutput{Content: input.Content}, nil
+	}
+
+	prompt := node.SystemInstruction()
+	if prompt == "" {
+		prompt = "Merge the following outputs from parallel branches into a single coherent response. Keep every distinct point and drop repetition."
+	}
+	model := e.resolver.ResolveModelName(node)
+	resp, err := e.chat(ctx, node, model, prompt, input.Content)
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.join", node.ID, err)
+	}
+
+	return NodeOutput{
+		Content:   resp.Content,
+		TokensIn:  resp.Usage.PromptTokens,
+		TokensOut: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// executeEmbedder indexes its input into the vector store as a side effect
+// and passes the content through unchanged.
+func (e *Executor) executeEmbedder(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	if e.vectorStore == nil {
+		return NodeOutput{}, core.NewAgentError("executor.embedder", node.ID, fmt.Errorf("%w: no vector store configured", core.ErrInvalidConfig))
+	}
+	embedder, ok := e.client.(llm.EmbeddingClient)
+	if !ok {
+		return NodeOutput{}, core.NewAgentError("executor.embedder", node.ID, fmt.Errorf("%w: client does not support embeddings", core.ErrInvalidConfig))
+	}
+
+	model := node.Model.Name
+	if model == "" {
+		model = e.embedModel
+	}
+
+	resp, err := embedder.Embed(ctx, model, input.Content)
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.embedder", node.ID, err)
+	}
+
+	metadata := map[string]any{
+		"node_id":  node.ID,
+		"trace_id": input.TraceID,
+	}
+	for k, v := range node.Metadata {
+		metadata[k] = v
+	}
+
+	doc := vector.Document{
+		ID:        input.TraceID,
+		Content:   input.Content,
+		Embedding: resp.Embedding,
+		Metadata:  metadata,
+	}
+	if err := e.vectorStore.Upsert(ctx, []vector.Document{doc}); err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.embedder", node.ID, err)
+	}
+
+	return NodeOutput{
+		Content:  input.Content,
+		TokensIn: resp.TokenCount,
+	}, nil
+}
+
+// executeSubpipeline runs the node's nested pipeline in a child engine that
+// shares this executor's client, registry and resolver.
+func (e *Executor) executeSubpipeline(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	if node.SubPipeline == nil {
+		return NodeOutput{}, core.NewAgentError("executor.subpipeline", node.ID, fmt.Errorf("%w: missing sub_pipeline", core.ErrInvalidConfig))
+	}
+
+	child := NewEngine(node.SubPipeline, EngineConfig{
+		Client:      e.client,
+		Registry:    e.registry,
+		Resolver:    e.resolver,
+		Logger:      e.logger,
+		VectorStore: e.vectorStore,
+		EmbedModel:  e.embedModel,
+
+		ModelFallbacks: e.fallbacks,
+		OptionalTools:  e.optionalTools,
+	})
+
+	result, err := child.Run(ctx, input.Content)
+	if err != nil {
+		return NodeOutput{}, core.NewAgentError("executor.subpipeline", node.ID, err)
+	}
+
+	var totalIn, totalOut int
+	for _, out := range result.Outputs {
+		totalIn += out.TokensIn
+		totalOut += out.TokensOut
+	}
+
+	return NodeOutput{
+		Content:   result.Content,
+		TokensIn:  totalIn,
+		TokensOut: totalOut,
+		Spans:     result.Spans,
+	}, nil
+}
+
+// executeLoop runs its child node repeatedly, feeding each result back in,
+// until the condition node answers "done" or MaxIter is reached.
+func (e *Executor) executeLoop(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeOutput, error) {
+	if len(node.TargetNodes) != 1 {
+		return NodeOutput{}, core.NewAgentError("executor.loop", node.ID, fmt.Errorf("%w: loop requires exactly one target node", core.ErrInvalidConfig))
+	}
+	child, ok := e.nodes[node.TargetNodes[0]]
+	if !ok {
+		return NodeOutput{}, core.NewAgentError("executor.loop", node.ID, fmt.Errorf("%w: %s", core.ErrNodeNotFound, node.TargetNodes[0]))
+	}
+	condition, ok := e.nodes[node.ConditionNode]
+	if !ok {
+		return NodeOutput{}, core.NewAgentError("executor.loop", node.ID, fmt.Errorf("%w: condition %q", core.ErrNodeNotFound, node.ConditionNode))
+	}
+
+	maxIter := node.MaxIter
+	if maxIter <= 0 {
+		maxIter = 10
+	}
+
+	var result NodeOutput
+	var spans []Span
+	content := input.Content
+
+	for i := 1; i <= maxIter; i++ {
+		iterStart := time.Now()
+		out, err := e.Execute(ctx, child, NodeInput{NodeID: child.ID, TraceID: input.TraceID, Content: content})
+		if err != nil {
+			return NodeOutput{}, core.WithContext(core.NewAgentError("executor.loop", node.ID, err), "iteration", i)
+		}
+
+		verdict, err := e.Execute(ctx, condition, NodeInput{NodeID: condition.ID, TraceID: input.TraceID, Content: out.Content})
+		if err != nil {
+			return NodeOutput{}, core.WithContext(core.NewAgentError("executor.loop", node.ID, err), "iteration", i)
+		}
+		iterEnd := time.Now()
+
+		result.TokensIn += out.TokensIn + verdict.TokensIn
+		result.TokensOut += out.TokensOut + verdict.TokensOut
+		spans = append(spans, Span{
+			SpanID:         fmt.Sprintf("%s_iter_%d", node.ID, i),
+			NodeID:         child.ID,
+			NodeType:       child.Type.String(),
+			StartTime:      iterStart.UnixMilli(),
+			EndTime:        iterEnd.UnixMilli(),
+			Input:          content,
+			Output:         out.Content,
+			InputTokens:    out.TokensIn + verdict.TokensIn,
+			OutputTokens:   out.TokensOut + verdict.TokensOut,
+			IterationCount: i,
+			Duration:       iterEnd.Sub(iterStart),
+		})
+
+		content = out.Content
+		if loopDone(verdict.Content) {
+			break
+		}
+	}
+
+	result.Content = content
+	result.Spans = spans
+	result.Metadata = map[string]any{"iterations": len(spans)}
+	return result, nil
+}
+
+func loopDone(verdict string) bool {
+	v := strings.ToLower(strings.Trim(strings.TrimSpace(verdict), `."'`))
+	return strings.HasPrefix(v, "done")
+}
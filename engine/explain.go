@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// explainPromptChars is how much of each prompt Explain previews.
+const explainPromptChars = 80
+
+// Explain describes the pipeline's execution plan: each node in topological
+// order with its type, model, tools, prompt preview and connections, followed
+// by warnings for cycles and nodes unreachable from the entry node's subgraph.
+// The output is deterministic so it can be compared in tests.
+func (e *Engine) Explain() string {
+	var sb strings.Builder
+	p := e.pipeline
+
+	entry := p.EntryNode
+	if entry == "" {
+		entry = e.findEntryNode()
+	}
+	fmt.Fprintf(&sb, "Pipeline %q (%s): %d nodes, entry %s\n", p.Name, p.ID, len(p.Nodes), entry)
+
+	var warnings []string
+	order, err := p.TopologicalOrder()
+	if err != nil {
+		warnings = append(warnings, "cycle detected; nodes listed in declaration order")
+		order = order[:0]
+		for _, n := range p.Nodes {
+			order = append(order, n.ID)
+		}
+	}
+
+	inbound := make(map[string][]string)
+	outbound := make(map[string][]string)
+	for _, edge := range p.Edges {
+		from, to := edge.From.Node, edge.To.Node
+		outbound[from] = append(outbound[from], to+describeEdge(edge))
+		inbound[to] = append(inbound[to], from+describeEdge(edge))
+	}
+
+	for i, id := range order {
+		node := e.nodeMap[id]
+		fmt.Fprintf(&sb, "\n%d. %s [%s]\n", i+1, id, node.Type)
+		if modelNodeTypes[node.Type] {
+			fmt.Fprintf(&sb, "   model: %s\n", e.executor.resolver.ResolveModelName(node))
+		}
+		if len(node.Tools) > 0 {
+			fmt.Fprintf(&sb, "   tools: %s\n", strings.Join(node.Tools, ", "))
+		}
+		if prompt := node.SystemInstruction(); prompt != "" {
+			fmt.Fprintf(&sb, "   prompt: %s\n", strconv.Quote(previewPrompt(prompt)))
+		}
+		if len(node.TargetNodes) > 0 {
+			fmt.Fprintf(&sb, "   targets: %s\n", strings.Join(node.TargetNodes, ", "))
+		}
+		if node.ConditionNode != "" {
+			fmt.Fprintf(&sb, "   condition node: %s\n", node.ConditionNode)
+		}
+		fmt.Fprintf(&sb, "   in: %s\n", listOrNone(inbound[id], id == entry))
+		fmt.Fprintf(&sb, "   out: %s\n", listOrNone(outbound[id], false))
+	}
+
+	for _, group := range e.disconnectedGroups(entry) {
+		warnings = append(warnings, "disconnected from entry: "+strings.Join(group, ", "))
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(&sb, "WARNING: %s\n", w)
+	}
+	return sb.String()
+}
+
+// describeEdge annotates non-default edges, e.g. ` (conditional: "billing")`.
+func describeEdge(edge config.EdgeConfig) string {
+	switch {
+	case edge.Type == config.EdgeDefault:
+		return ""
+	case edge.Condition != "":
+		return fmt.Sprintf(" (%s: %q)", edge.Type, edge.Condition)
+	default:
+		return fmt.Sprintf(" (%s)", edge.Type)
+	}
+}
+
+func listOrNone(items []string, entry bool) string {
+	if entry {
+		items = append([]string{"(entry)"}, items...)
+	}
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
+}
+
+// previewPrompt returns the prompt's first explainPromptChars characters on one line.
+func previewPrompt(prompt string) string {
+	prompt = strings.Join(strings.Fields(prompt), " ")
+	runes := []rune(prompt)
+	if len(runes) <= explainPromptChars {
+		return prompt
+	}
+	return string(runes[:explainPromptChars]) + "..."
+}
+
+// disconnectedGroups returns the weakly connected groups of nodes that do not
+// contain entry, each in declaration order. Edges, target nodes and loop
+// condition nodes all count as connections.
+func (e *Engine) disconnectedGroups(entry string) [][]string {
+	neighbors := make(map[string][]string)
+	link := func(a, b string) {
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+	for _, edge := range e.pipeline.Edges {
+		link(edge.From.Node, edge.To.Node)
+	}
+	for _, n := range e.pipeline.Nodes {
+		for _, t := range n.TargetNodes {
+			link(n.ID, t)
+		}
+		if n.ConditionNode != "" {
+			link(n.ID, n.ConditionNode)
+		}
+	}
+
+	group := make(map[string]int)
+	next := 0
+	for _, n := range e.pipeline.Nodes {
+		if _, seen := group[n.ID]; seen {
+			continue
+		}
+		stack := []string{n.ID}
+		group[n.ID] = next
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, nb := range neighbors[id] {
+				if _, seen := group[nb]; !seen {
+					group[nb] = next
+					stack = append(stack, nb)
+				}
+			}
+		}
+		next++
+	}
+
+	groups := make([][]string, next)
+	for _, n := range e.pipeline.Nodes {
+		if g := group[n.ID]; g != group[entry] {
+			groups[g] = append(groups[g], n.ID)
+		}
+	}
+
+	var result [][]string
+	for _, g := range groups {
+		if len(g) > 0 {
+			result = append(result, g)
+		}
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// ConversationMemory keeps the messages of a multi-turn conversation so each
+// Run sees earlier turns. It is safe for concurrent use.
+type ConversationMemory struct {
+	MaxMessages int // Optional: keep only the most recent messages (0 = unlimited)
+
+	mu       sync.Mutex
+	messages []core.Message
+}
+
+func NewConversationMemory(maxMessages int) *ConversationMemory {
+	return &ConversationMemory{MaxMessages: maxMessages}
+}
+
+// Add appends a message, dropping the oldest once MaxMessages is exceeded.
+func (m *ConversationMemory) Add(role, content string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, core.Message{Role: core.MessageRole(role), Content: content})
+	if m.MaxMessages > 0 && len(m.messages) > m.MaxMessages {
+		m.messages = append([]core.Message(nil), m.messages[len(m.messages)-m.MaxMessages:]...)
+	}
+}
+
+// Messages returns a copy of the stored messages, oldest first.
+func (m *ConversationMemory) Messages() []core.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]core.Message(nil), m.messages...)
+}
+
+// Clear removes all stored messages.
+func (m *ConversationMemory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
+
+// prependTo renders the stored conversation as a transcript ahead of input.
+func (m *ConversationMemory) prependTo(input string) string {
+	msgs := m.Messages()
+	if len(msgs) == 0 {
+		return input
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Conversation so far:\n")
+	for _, msg := range msgs {
+		sb.WriteString(string(msg.Role) + ": " + msg.Content + "\n")
+	}
+	sb.WriteString("\nCurrent message:\n")
+	sb.WriteString(input)
+	return sb.String()
+}
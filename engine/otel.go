@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+const otelScope = "github.com/hubenschmidt/go-fissio/engine"
+
+type pipelineIDKey struct{}
+
+func withPipelineID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, pipelineIDKey{}, id)
+}
+
+// OTELExporter sends engine spans to an OTLP endpoint through an otlptrace.Client
+// (e.g. otlptracehttp.NewClient or otlptracegrpc.NewClient).
+type OTELExporter struct {
+	client      otlptrace.Client
+	serviceName string
+
+	startOnce sync.Once
+	startErr  error
+}
+
+// NewOTELExporter creates an exporter reporting as service "fissio".
+// The client is started on the first Export.
+func NewOTELExporter(client otlptrace.Client) *OTELExporter {
+	return &OTELExporter{client: client, serviceName: "fissio"}
+}
+
+// Export uploads spans, including their children, as one OTLP trace.
+// Non-hex trace IDs such as "trace_<nanos>" are hashed to the 16-byte form OTLP requires.
+func (x *OTELExporter) Export(ctx context.Context, spans []Span, traceID string) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	x.startOnce.Do(func() { x.startErr = x.client.Start(ctx) })
+	if x.startErr != nil {
+		return fmt.Errorf("start otlp client: %w", x.startErr)
+	}
+
+	pipelineID, _ := ctx.Value(pipelineIDKey{}).(string)
+	tid := otelTraceID(traceID)
+
+	var out []*tracepb.Span
+	var walk func(spans []Span, parent []byte)
+	walk = func(spans []Span, parent []byte) {
+		for _, s := range spans {
+			ps := otelSpan(s, tid, parent, pipelineID)
+			out = append(out, ps)
+			walk(s.Children, ps.SpanId)
+		}
+	}
+	walk(spans, nil)
+
+	resourceSpans := []*tracepb.ResourceSpans{{
+		Resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{otelString("service.name", x.serviceName)},
+		},
+		ScopeSpans: []*tracepb.ScopeSpans{{
+			Scope: &commonpb.InstrumentationScope{Name: otelScope},
+			Spans: out,
+		}},
+	}}
+	if err := x.client.UploadTraces(ctx, resourceSpans); err != nil {
+		return fmt.Errorf("upload traces: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops the underlying client, flushing any buffered data.
+func (x *OTELExporter) Shutdown(ctx context.Context) error {
+	return x.client.Stop(ctx)
+}
+
+func otelSpan(s Span, traceID, parent []byte, pipelineID string) *tracepb.Span {
+	attrs := []*commonpb.KeyValue{
+		otelString("fissio.pipeline.id", pipelineID),
+		otelString("fissio.node.id", s.NodeID),
+		otelString("fissio.node.type", s.NodeType),
+		otelInt("llm.token.input", s.InputTokens),
+		otelInt("llm.token.output", s.OutputTokens),
+	}
+	if s.ToolCallCount > 0 {
+		attrs = append(attrs, otelInt("fissio.tool_call.count", s.ToolCallCount))
+	}
+	if s.IterationCount > 0 {
+		attrs = append(attrs, otelInt("fissio.iteration", s.IterationCount))
+	}
+
+	status := &tracepb.Status{Code: tracepb.Status_STATUS_CODE_OK}
+	if timedOut, _ := s.Metadata["timed_out"].(bool); timedOut {
+		status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "timed out"}
+	}
+
+	return &tracepb.Span{
+		TraceId:           traceID,
+		SpanId:            otelSpanID(traceID, parent, s.SpanID),
+		ParentSpanId:      parent,
+		Name:              s.NodeID,
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(s.StartTime) * 1e6,
+		EndTimeUnixNano:   uint64(s.EndTime) * 1e6,
+		Attributes:        attrs,
+		Status:            status,
+	}
+}
+
+func otelTraceID(traceID string) []byte {
+	if b, err := hex.DecodeString(traceID); err == nil && len(b) == 16 {
+		return b
+	}
+	sum := sha256.Sum256([]byte(traceID))
+	return sum[:16]
+}
+
+// otelSpanID derives a stable 8-byte ID; the parent is mixed in because child
+// span IDs (e.g. "worker_iter_1") repeat across parents.
+func otelSpanID(traceID, parent []byte, spanID string) []byte {
+	h := sha256.New()
+	h.Write(traceID)
+	h.Write(parent)
+	h.Write([]byte(spanID))
+	return h.Sum(nil)[:8]
+}
+
+func otelString(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func otelInt(key string, value int) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(value)}}}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+var jsonFence = regexp.MustCompile("(?s)```(?:json)?\\s*\\n?(.*?)```")
+
+// parseOutput extracts fields from content with p. JSON objects contribute
+// their top-level keys; other JSON values are returned under "parsed". The
+// whole JSON value is also returned under "structured" for GetTyped, unless
+// the object has its own "structured" key.
+func parseOutput(p *config.OutputParser, content string) (map[string]any, error) {
+	switch p.Type {
+	case config.ParserJSON:
+		return parseJSONFields(strings.TrimSpace(content))
+	case config.ParserJSONBlock:
+		if m := jsonFence.FindStringSubmatch(content); m != nil {
+			return parseJSONFields(strings.TrimSpace(m[1]))
+		}
+		return parseJSONFields(strings.TrimSpace(content))
+	case config.ParserRegex:
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern: %w", err)
+		}
+		m := re.FindStringSubmatch(content)
+		if m == nil {
+			return nil, fmt.Errorf("pattern %q did not match output", p.Pattern)
+		}
+		fields := make(map[string]any)
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				fields[name] = m[i]
+			}
+		}
+		return fields, nil
+	case config.ParserPassthrough, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown output parser %q", p.Type)
+	}
+}
+
+func parseJSONFields(s string) (map[string]any, error) {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return map[string]any{"parsed": v, "structured": v}, nil
+	}
+	fields := maps.Clone(obj)
+	if _, ok := fields["structured"]; !ok {
+		fields["structured"] = obj
+	}
+	return fields, nil
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const prettyRule = "══════════════════════════════════════════════════════════════"
+
+// PrettyHandler is a slog.Handler that renders engine events in the
+// box-drawing layout of the original console output. pipeline_start and
+// node_start are logged at debug level, so set opts.Level to
+// slog.LevelDebug for the full box. Other records print as a plain line.
+type PrettyHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+// NewPrettyHandler creates a PrettyHandler writing to w. opts may be nil.
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) *PrettyHandler {
+	h := &PrettyHandler{w: w, mu: &sync.Mutex{}, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr(nil), h.attrs...), h.qualify(attrs)...)
+	return &clone
+}
+
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
+
+func (h *PrettyHandler) qualify(attrs []slog.Attr) []slog.Attr {
+	if h.group == "" {
+		return attrs
+	}
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: h.group + a.Key, Value: a.Value}
+	}
+	return out
+}
+
+func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]slog.Value, len(h.attrs)+r.NumAttrs())
+	keys := make([]string, 0, len(h.attrs)+r.NumAttrs())
+	add := func(a slog.Attr) {
+		if _, ok := attrs[a.Key]; !ok {
+			keys = append(keys, a.Key)
+		}
+		attrs[a.Key] = a.Value.Resolve()
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		for _, qa := range h.qualify([]slog.Attr{a}) {
+			add(qa)
+		}
+		return true
+	})
+
+	get := func(key string) string {
+		if v, ok := attrs[key]; ok {
+			return v.String()
+		}
+		return ""
+	}
+
+	var sb strings.Builder
+	line := func(format string, args ...any) {
+		sb.WriteString(fmt.Sprintf(format, args...))
+		sb.WriteByte('\n')
+	}
+
+	switch r.Message {
+	case "pipeline_start":
+		line("╔" + prettyRule)
+		line("║ PIPELINE: %s", get("pipeline"))
+		line("║ Input: %s chars", get("input_chars"))
+		line("╠" + prettyRule)
+	case "node_start":
+		line("║ [%s] NODE: %s (%s)", get("step"), get("node_id"), get("node_type"))
+		line("║     Model: %s", get("model"))
+		if tools := get("tools"); tools != "" && tools != "[]" {
+			line("║     Tools: %s", tools)
+		}
+	case "node_complete":
+		line("║     ✓ Completed in %s", get("duration"))
+		line("║     ← Response: %s chars, %s/%s tokens", get("output_chars"), get("tokens_in"), get("tokens_out"))
+	case "node_failed":
+		line("║     ✗ Error: %s", get("error"))
+	case "pipeline_complete":
+		line("║ Pipeline complete in %s", get("duration"))
+		line("║ Output: %s chars", get("output_chars"))
+		line("╚" + prettyRule)
+	default:
+		sb.WriteString(fmt.Sprintf("║ %s %s", r.Level, r.Message))
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf(" %s=%s", k, attrs[k]))
+		}
+		sb.WriteByte('\n')
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, sb.String())
+	return err
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// reactMarkers are the section labels of the ReAct Thought/Action/Observation format.
+var reactMarkers = []string{"Thought:", "Action:", "Action Input:", "Observation:", "Final Answer:"}
+
+// reactStep extracts the thought and action of one worker iteration. The action
+// falls back to the native tool calls when the reply has no Action section.
+// It returns nil when the reply contains neither.
+func reactStep(content string, calls []core.ToolCall) map[string]any {
+	thought := reactSection(content, "Thought:")
+	action := reactSection(content, "Action:")
+	if input := reactSection(content, "Action Input:"); action != "" && input != "" {
+		action += " " + input
+	}
+
+	if action == "" && len(calls) > 0 {
+		names := make([]string, len(calls))
+		for i, c := range calls {
+			names[i] = fmt.Sprintf("%s(%s)", c.Name, c.Arguments)
+		}
+		action = strings.Join(names, "; ")
+	}
+
+	if thought == "" && action == "" {
+		return nil
+	}
+	return map[string]any{"thought": thought, "action": action}
+}
+
+// reactSection returns the text after the last occurrence of marker, up to the next marker.
+func reactSection(content, marker string) string {
+	start := strings.LastIndex(content, marker)
+	if start < 0 {
+		return ""
+	}
+	rest := content[start+len(marker):]
+
+	end := len(rest)
+	for _, m := range reactMarkers {
+		if i := strings.Index(rest, m); i >= 0 && i < end {
+			end = i
+		}
+	}
+	return strings.TrimSpace(rest[:end])
+}
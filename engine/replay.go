@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hubenschmidt/go-fissio/core"
+	"github.com/hubenschmidt/go-fissio/server/store"
+)
+
+type replayKey struct{}
+
+func withReplay(ctx context.Context, outputs map[string]NodeOutput) context.Context {
+	return context.WithValue(ctx, replayKey{}, outputs)
+}
+
+// replayedOutput returns the recorded output Replay substitutes for nodeID, if any.
+func replayedOutput(ctx context.Context, nodeID string) (NodeOutput, bool) {
+	outputs, _ := ctx.Value(replayKey{}).(map[string]NodeOutput)
+	out, ok := outputs[nodeID]
+	return out, ok
+}
+
+// Replay re-runs a recorded trace with the same input, reusing each node's
+// traced output instead of executing it, except for EngineConfig.OverrideNode,
+// which runs live. This reproduces one node's behaviour in the exact context it
+// saw in production. A replayed node continues to the nodes that ran after it
+// in the trace; nodes without a recorded output (timed out, or not reached)
+// run live.
+func (e *Engine) Replay(ctx context.Context, trace *store.TraceInfo) (*EngineOutput, error) {
+	if trace == nil {
+		return nil, core.NewAgentError("engine.replay", "", fmt.Errorf("%w: nil trace", core.ErrInvalidConfig))
+	}
+	if e.overrideNode != "" && e.nodeMap[e.overrideNode] == nil {
+		return nil, core.NewAgentError("engine.replay", e.overrideNode, core.ErrNodeNotFound)
+	}
+
+	ran := make(map[string]bool, len(trace.Spans))
+	for _, span := range trace.Spans {
+		ran[span.NodeID] = true
+	}
+
+	recorded := make(map[string]NodeOutput, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if span.NodeID == e.overrideNode || e.nodeMap[span.NodeID] == nil || span.Metadata["timed_out"] == true {
+			continue
+		}
+		var next []string
+		for _, target := range e.edges[span.NodeID] {
+			if ran[target] {
+				next = append(next, target)
+			}
+		}
+		recorded[span.NodeID] = NodeOutput{
+			NodeID:    span.NodeID,
+			Content:   span.Output,
+			NextNodes: next,
+		}
+	}
+
+	return e.Run(withReplay(ctx, recorded), trace.Input)
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"maps"
+
 	"github.com/hubenschmidt/go-fissio/config"
 	"github.com/hubenschmidt/go-fissio/core"
 )
@@ -8,6 +10,7 @@ import (
 type ModelResolver struct {
 	defaultModel core.ModelConfig
 	overrides    map[string]core.ModelConfig
+	modelNames   map[string]string
 }
 
 func NewModelResolver(defaultModel core.ModelConfig) *ModelResolver {
@@ -21,7 +24,31 @@ func (r *ModelResolver) SetOverride(nodeID string, model core.ModelConfig) {
 	r.overrides[nodeID] = model
 }
 
+// WithModelOverrides returns a copy of r that swaps in the model name for each
+// node ID in names, keeping the node's other model settings. r is unchanged.
+func (r *ModelResolver) WithModelOverrides(names map[string]string) *ModelResolver {
+	resolved := &ModelResolver{
+		defaultModel: r.defaultModel,
+		overrides:    maps.Clone(r.overrides),
+		modelNames:   maps.Clone(r.modelNames),
+	}
+	if resolved.modelNames == nil {
+		resolved.modelNames = make(map[string]string, len(names))
+	}
+	maps.Copy(resolved.modelNames, names)
+	return resolved
+}
+
 func (r *ModelResolver) Resolve(node *config.NodeConfig) core.ModelConfig {
+	if name, ok := r.modelNames[node.ID]; ok && name != "" {
+		model := r.defaultModel
+		if node.Model.Name != "" {
+			model = node.Model
+		}
+		model.Name = name
+		return model
+	}
+
 	if override, ok := r.overrides[node.ID]; ok {
 		return override
 	}
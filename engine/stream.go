@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+type eventSinkKey struct{}
+
+type nodeCallbackKey struct{}
+
+func withEventSink(ctx context.Context, emit func(EngineEvent)) context.Context {
+	return context.WithValue(ctx, eventSinkKey{}, emit)
+}
+
+// emitEvent sends ev to the RunStream consumer, if any.
+func emitEvent(ctx context.Context, ev EngineEvent) {
+	if emit, ok := ctx.Value(eventSinkKey{}).(func(EngineEvent)); ok {
+		emit(ev)
+	}
+}
+
+// notifyNodeOutput passes a copy of out to the RunWithCallback callback, if any.
+func notifyNodeOutput(ctx context.Context, out NodeOutput) {
+	cb, ok := ctx.Value(nodeCallbackKey{}).(func(NodeOutput))
+	if !ok {
+		return
+	}
+	out.NextNodes = slices.Clone(out.NextNodes)
+	out.Metadata = maps.Clone(out.Metadata)
+	out.Spans = slices.Clone(out.Spans)
+	cb(out)
+}
+
+func streaming(ctx context.Context) bool {
+	_, ok := ctx.Value(eventSinkKey{}).(func(EngineEvent))
+	return ok
+}
+
+// RunStream runs the pipeline in the background and reports progress on the
+// returned channel: node start/completion, token chunks from streaming LLM
+// nodes, and a final PipelineDone or Error event. The channel is closed once
+// the pipeline finishes.
+func (e *Engine) RunStream(ctx context.Context, input string) (<-chan EngineEvent, error) {
+	if e.pipeline.EntryNode == "" && e.findEntryNode() == "" {
+		return nil, core.NewAgentError("engine.run", "", core.ErrNodeNotFound)
+	}
+	if e.cycleErr != nil {
+		return nil, core.NewAgentError("engine.run", "", e.cycleErr)
+	}
+
+	events := make(chan EngineEvent, 64)
+	send := func(ev EngineEvent) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(events)
+		out, err := e.Run(withEventSink(ctx, send), input)
+		if err != nil {
+			send(EngineEvent{Type: EventError, Output: out, Err: err})
+			return
+		}
+		send(EngineEvent{Type: EventPipelineDone, Content: out.Content, Output: out})
+	}()
+
+	return events, nil
+}
+
+// RunWithCallback runs the pipeline like Run, calling cb with a copy of each
+// node's output as soon as the node completes.
+func (e *Engine) RunWithCallback(ctx context.Context, input string, cb func(NodeOutput)) (*EngineOutput, error) {
+	return e.Run(context.WithValue(ctx, nodeCallbackKey{}, cb), input)
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/hubenschmidt/go-fissio/config"
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+type variablesKey struct{}
+
+func withVariables(ctx context.Context, vars map[string]any) context.Context {
+	return context.WithValue(ctx, variablesKey{}, vars)
+}
+
+func variables(ctx context.Context) map[string]any {
+	vars, _ := ctx.Value(variablesKey{}).(map[string]any)
+	return vars
+}
+
+// templateData is what a node's InputTemplate sees: the NodeInput fields
+// plus the run's variables as .Vars.
+type templateData struct {
+	NodeInput
+	Vars map[string]any
+}
+
+// outputTemplateData is what a node's OutputTransform sees.
+type outputTemplateData struct {
+	Content  string
+	Metadata map[string]any
+	Vars     map[string]any
+}
+
+// applyOutputTransform replaces output.Content with node.OutputTransform
+// executed against the output and the run's variables.
+func applyOutputTransform(ctx context.Context, node *config.NodeConfig, output NodeOutput) (NodeOutput, error) {
+	tmpl, err := config.ParseOutputTransform(node)
+	if err != nil {
+		return output, core.NewAgentError("executor.transform", node.ID, err)
+	}
+
+	var sb strings.Builder
+	data := outputTemplateData{Content: output.Content, Metadata: output.Metadata, Vars: variables(ctx)}
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return output, core.NewAgentError("executor.transform", node.ID, err)
+	}
+	output.Content = sb.String()
+	return output, nil
+}
+
+// renderInputTemplate replaces input.Content with node.InputTemplate
+// executed against the input and the run's variables.
+func renderInputTemplate(ctx context.Context, node *config.NodeConfig, input NodeInput) (NodeInput, error) {
+	tmpl, err := template.New(node.ID).Parse(node.InputTemplate)
+	if err != nil {
+		return input, core.NewAgentError("executor.template", node.ID, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, templateData{NodeInput: input, Vars: variables(ctx)}); err != nil {
+		return input, core.NewAgentError("executor.template", node.ID, err)
+	}
+	input.Content = sb.String()
+	return input, nil
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/hubenschmidt/go-fissio/config"
+	"github.com/hubenschmidt/go-fissio/llm"
+	"github.com/hubenschmidt/go-fissio/vector"
+)
+
+// truncationMarker replaces the text dropped by TruncateMiddle.
+const truncationMarker = "\n...\n"
+
+// contextBudget is the share of a model's context window the input and system
+// prompt may fill when MaxInputTokens is unset, leaving room for the reply.
+const contextBudget = 0.9
+
+// inputTokenLimit returns node.MaxInputTokens, or for model nodes without it,
+// 90% of the model's context window less the system prompt. 0 means no limit.
+func (e *Executor) inputTokenLimit(node *config.NodeConfig) int {
+	if node.MaxInputTokens > 0 || !modelNodeTypes[node.Type] {
+		return node.MaxInputTokens
+	}
+
+	model := e.resolver.Resolve(node)
+	window := model.MaxContextTokens
+	if window <= 0 {
+		window = llm.ModelContextWindow(model.Name)
+	}
+	if window <= 0 {
+		return 0
+	}
+	return max(int(float64(window)*contextBudget)-vector.EstimateTokens(node.SystemInstruction()), 1)
+}
+
+// truncateInput shortens input.Content to about limit tokens, estimated at 4
+// characters per token, dropping the portion named by node.TruncationStrategy.
+func (e *Executor) truncateInput(ctx context.Context, node *config.NodeConfig, input NodeInput, limit int) NodeInput {
+	if vector.EstimateTokens(input.Content) <= limit {
+		return input
+	}
+
+	original := len(input.Content)
+	maxChars := limit * 4
+	content := input.Content
+
+	strategy := node.TruncationStrategy
+	if strategy == "" {
+		strategy = config.TruncateEnd
+	}
+	switch strategy {
+	case config.TruncateStart:
+		input.Content = content[runeStart(content, len(content)-maxChars):]
+	case config.TruncateMiddle:
+		keep := max(maxChars-len(truncationMarker), 0)
+		head := runeStart(content, keep/2)
+		tail := runeStart(content, len(content)-(keep-keep/2))
+		input.Content = content[:head] + truncationMarker + content[tail:]
+	default:
+		input.Content = content[:runeStart(content, maxChars)]
+	}
+
+	e.logger.WarnContext(ctx, "input_truncated",
+		slog.String("node_id", node.ID),
+		slog.String("strategy", strategy),
+		slog.Int("max_input_tokens", limit),
+		slog.Int("original_chars", original),
+		slog.Int("truncated_chars", len(input.Content)),
+	)
+	return input
+}
+
+// runeStart moves i back to the start of the UTF-8 rune containing it.
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
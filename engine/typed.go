@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GetTyped decodes the structured output of nodeID into T. Structured output
+// is Metadata["structured"], set by nodes with a ResponseSchema or a json or
+// json_block OutputParser. Go methods cannot take type parameters, so this is
+// a function: engine.GetTyped[Verdict](result, "validator").
+func GetTyped[T any](out *EngineOutput, nodeID string) (T, error) {
+	var v T
+	if out == nil {
+		return v, fmt.Errorf("get typed output of %s: nil engine output", nodeID)
+	}
+	node, ok := out.Outputs[nodeID]
+	if !ok {
+		return v, fmt.Errorf("get typed output of %s: node did not run", nodeID)
+	}
+	structured, ok := node.Metadata["structured"]
+	if !ok {
+		return v, fmt.Errorf("get typed output of %s: no structured output (set ResponseSchema or a json OutputParser)", nodeID)
+	}
+
+	data, err := json.Marshal(structured)
+	if err != nil {
+		return v, fmt.Errorf("get typed output of %s: marshal: %w", nodeID, err)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("get typed output of %s: unmarshal into %T: %w", nodeID, v, err)
+	}
+	return v, nil
+}
+
+// MustGetTyped is GetTyped that panics on error, for tests and examples.
+func MustGetTyped[T any](out *EngineOutput, nodeID string) T {
+	v, err := GetTyped[T](out, nodeID)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -4,9 +4,11 @@ import "time"
 
 type NodeInput struct {
 	NodeID   string         `json:"node_id"`
+	TraceID  string         `json:"trace_id,omitempty"`
 	Content  string         `json:"content"`
 	Metadata map[string]any `json:"metadata,omitempty"`
 	Sources  []string       `json:"sources,omitempty"`
+	Parts    []string       `json:"parts,omitempty"` // output of each source that has run, in Sources order
 }
 
 type NodeOutput struct {
@@ -17,30 +19,68 @@ type NodeOutput struct {
 	TokensIn  int            `json:"tokens_in,omitempty"`
 	TokensOut int            `json:"tokens_out,omitempty"`
 	Duration  time.Duration  `json:"duration,omitempty"`
+	Spans     []Span         `json:"spans,omitempty"`
+
+	branches []NodeOutput // outputs of the targets a parallel node ran itself
 }
 
 type Span struct {
-	SpanID        string        `json:"span_id"`
-	NodeID        string        `json:"node_id"`
-	NodeType      string        `json:"node_type"`
-	StartTime     int64         `json:"start_time"`
-	EndTime       int64         `json:"end_time"`
-	Input         string        `json:"input"`
-	Output        string        `json:"output"`
-	InputTokens   int           `json:"input_tokens"`
-	OutputTokens  int           `json:"output_tokens"`
-	ToolCallCount int           `json:"tool_call_count"`
-	Duration      time.Duration `json:"duration"`
+	SpanID         string         `json:"span_id"`
+	NodeID         string         `json:"node_id"`
+	NodeType       string         `json:"node_type"`
+	StartTime      int64          `json:"start_time"`
+	EndTime        int64          `json:"end_time"`
+	Input          string         `json:"input"`
+	Output         string         `json:"output"`
+	InputTokens    int            `json:"input_tokens"`
+	OutputTokens   int            `json:"output_tokens"`
+	ToolCallCount  int            `json:"tool_call_count"`
+	IterationCount int            `json:"iteration_count,omitempty"`
+	Duration       time.Duration  `json:"duration"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	Children       []Span         `json:"children,omitempty"`
 }
 
 type EngineOutput struct {
-	Success   bool                  `json:"success"`
-	FinalNode string                `json:"final_node"`
-	Content   string                `json:"content"`
-	Outputs   map[string]NodeOutput `json:"outputs"`
-	Spans     []Span                `json:"spans"`
-	Error     error                 `json:"error,omitempty"`
-	Duration  time.Duration         `json:"duration"`
+	TraceID          string                `json:"trace_id"`
+	Success          bool                  `json:"success"`
+	FinalNode        string                `json:"final_node"`
+	Content          string                `json:"content"`
+	Outputs          map[string]NodeOutput `json:"outputs"`
+	Spans            []Span                `json:"spans"`
+	Error            error                 `json:"error,omitempty"`
+	Duration         time.Duration         `json:"duration"`
+	EstimatedCostUSD float64               `json:"estimated_cost_usd"`
+}
+
+// EngineEventType identifies the kind of event emitted by RunStream.
+type EngineEventType string
+
+const (
+	EventNodeStarted   EngineEventType = "node_started"
+	EventNodeCompleted EngineEventType = "node_completed"
+	EventTokenChunk    EngineEventType = "token_chunk"
+	EventPipelineDone  EngineEventType = "pipeline_done"
+	EventError         EngineEventType = "error"
+)
+
+// EngineEvent is a single progress update from RunStream. Only the fields
+// relevant to Type are set: NodeID for node and token events, Content for
+// token chunks and completed nodes, Output for PipelineDone and Error.
+type EngineEvent struct {
+	Type     EngineEventType `json:"type"`
+	NodeID   string          `json:"node_id,omitempty"`
+	NodeType string          `json:"node_type,omitempty"`
+	Content  string          `json:"content,omitempty"`
+	Output   *EngineOutput   `json:"output,omitempty"`
+	Err      error           `json:"-"`
+}
+
+// DryRunReport lists problems DryRun found without executing the pipeline.
+type DryRunReport struct {
+	MissingTools       []string `json:"missing_tools"`
+	UnresolvableModels []string `json:"unresolvable_models"`
+	EstimatedMaxNodes  int      `json:"estimated_max_nodes"` // Upper bound on node executions, counting every loop iteration
 }
 
 type ExecutionContext struct {
@@ -61,6 +101,17 @@ func (c *ExecutionContext) AddOutput(out NodeOutput) {
 	c.History = append(c.History, out)
 }
 
+// SetVar sets a run variable, visible to later nodes as NodeInput.Metadata
+// and as .Vars in input templates.
+func (c *ExecutionContext) SetVar(key string, val any) {
+	c.Variables[key] = val
+}
+
+func (c *ExecutionContext) GetVar(key string) (any, bool) {
+	val, ok := c.Variables[key]
+	return val, ok
+}
+
 func (c *ExecutionContext) GetOutput(nodeID string) (NodeOutput, bool) {
 	for i := len(c.History) - 1; i >= 0; i-- {
 		if c.History[i].NodeID == nodeID {
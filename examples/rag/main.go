@@ -100,7 +100,7 @@ func main() {
 
 	// Create vector store
 	log.Printf("[vector] Connecting to PostgreSQL with pgvector...")
-	store, err := vector.NewPgVectorStore(databaseURL, 1536)
+	store, err := vector.NewPgVectorStore(vector.PgVectorConfig{DSN: databaseURL, Dimension: 1536})
 	if err != nil {
 		log.Fatalf("Failed to create vector store: %v", err)
 	}
@@ -329,20 +329,19 @@ func indexNewDocument(ctx context.Context, content string, client *llm.UnifiedCl
 }
 
 func listDocuments(ctx context.Context, store *vector.PgVectorStore) {
-	// Search with a generic embedding to list all docs (hacky but works)
-	results, err := store.Search(ctx, make([]float64, 1536), 100)
+	docs, total, err := store.List(ctx, 100, 0)
 	if err != nil {
 		fmt.Printf("Error listing documents: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Indexed documents (%d):\n", len(results))
-	for _, r := range results {
-		preview := r.Document.Content
+	fmt.Printf("Indexed documents (%d):\n", total)
+	for _, doc := range docs {
+		preview := doc.Content
 		if len(preview) > 60 {
 			preview = preview[:60] + "..."
 		}
-		fmt.Printf("  - %s: %s\n", r.Document.ID, preview)
+		fmt.Printf("  - %s: %s\n", doc.ID, preview)
 	}
 }
 
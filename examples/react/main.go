@@ -0,0 +1,121 @@
+// ReAct agent demo using go-fissio's ReAct pipeline template.
+//
+// This example:
+// 1. Registers a small date arithmetic tool
+// 2. Builds a single-worker ReAct pipeline that reasons in Thought/Action/Observation steps
+// 3. Prints each iteration's thought and action from the trace spans, then the final answer
+//
+// Usage:
+//
+//	go run ./examples/react "How many days are there from 2024-02-01 to 2024-03-15?"
+//
+// Environment variables:
+//   - OPENAI_API_KEY: Required for chat
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio"
+	"github.com/hubenschmidt/go-fissio/pipelines"
+)
+
+// daysBetweenTool counts the days between two ISO dates.
+type daysBetweenTool struct{}
+
+func (daysBetweenTool) Name() string {
+	return "days_between"
+}
+
+func (daysBetweenTool) Description() string {
+	return "Count the number of days from one date to another. Dates use the YYYY-MM-DD format."
+}
+
+func (daysBetweenTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"from": {"type": "string", "description": "Start date (YYYY-MM-DD)"},
+			"to": {"type": "string", "description": "End date (YYYY-MM-DD)"}
+		},
+		"required": ["from", "to"]
+	}`)
+}
+
+func (daysBetweenTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var req struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if err := json.Unmarshal(args, &req); err != nil {
+		return "", fmt.Errorf("parse args: %w", err)
+	}
+
+	from, err := time.Parse(time.DateOnly, req.From)
+	if err != nil {
+		return "", fmt.Errorf("parse from: %w", err)
+	}
+	to, err := time.Parse(time.DateOnly, req.To)
+	if err != nil {
+		return "", fmt.Errorf("parse to: %w", err)
+	}
+	return fmt.Sprintf("%d days", int(to.Sub(from).Hours()/24)), nil
+}
+
+func main() {
+	ctx := context.Background()
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		log.Fatal("OPENAI_API_KEY environment variable is required")
+	}
+
+	question := "How many days are there from 2024-02-01 to 2024-03-15, and is that more than six weeks?"
+	if len(os.Args) > 1 {
+		question = strings.Join(os.Args[1:], " ")
+	}
+
+	client := fissio.NewUnifiedClient(fissio.UnifiedConfig{
+		OpenAIKey: apiKey,
+	})
+
+	fissio.RegisterTool(daysBetweenTool{})
+
+	pipeline := pipelines.NewReActPipeline(
+		"You are a careful assistant that answers questions about dates.",
+		[]string{"days_between"},
+	)
+
+	engine := fissio.NewEngine(pipeline, fissio.EngineConfig{
+		Client: client,
+	})
+
+	fmt.Printf("Question: %s\n\n", question)
+
+	result, err := engine.Run(ctx, question)
+	if err != nil {
+		log.Fatalf("Pipeline failed: %v", err)
+	}
+
+	// Each worker iteration is a child span carrying its thought and action
+	for _, span := range result.Spans {
+		for _, iter := range span.Children {
+			fmt.Printf("--- Step %d ---\n", iter.IterationCount)
+			if thought, _ := iter.Metadata["thought"].(string); thought != "" {
+				fmt.Printf("Thought: %s\n", thought)
+			}
+			if action, _ := iter.Metadata["action"].(string); action != "" {
+				fmt.Printf("Action:  %s\n", action)
+			}
+			fmt.Println()
+		}
+	}
+
+	fmt.Println(result.Content)
+}
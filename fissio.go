@@ -44,6 +44,13 @@ const (
 	NodeEvaluator    = config.NodeEvaluator
 	NodeSynthesizer  = config.NodeSynthesizer
 	NodeCoordinator  = config.NodeCoordinator
+	NodeParallel     = config.NodeParallel
+	NodeJoin         = config.NodeJoin
+	NodeEmbedder     = config.NodeEmbedder
+	NodeSubpipeline  = config.NodeSubpipeline
+	NodeLoop         = config.NodeLoop
+	NodeFanOut       = config.NodeFanOut
+	NodeSplitter     = config.NodeSplitter
 )
 
 // Builder aliases
@@ -51,6 +58,8 @@ type (
 	PipelineBuilder = config.PipelineBuilder
 	NodeBuilder     = config.NodeBuilder
 	PipelineConfig  = config.PipelineConfig
+	ValidationError = config.ValidationError
+	ValidationRule  = config.ValidationRule
 )
 
 // NewPipeline creates a new pipeline builder.
@@ -63,6 +72,10 @@ type (
 	Engine       = engine.Engine
 	EngineConfig = engine.EngineConfig
 	EngineOutput = engine.EngineOutput
+	EngineEvent  = engine.EngineEvent
+	DryRunReport = engine.DryRunReport
+
+	ConversationMemory = engine.ConversationMemory
 )
 
 // NewEngine creates a new pipeline execution engine.
@@ -70,6 +83,11 @@ func NewEngine(pipeline *PipelineConfig, cfg EngineConfig) *Engine {
 	return engine.NewEngine(pipeline, cfg)
 }
 
+// GetTyped decodes a node's structured output into T.
+func GetTyped[T any](out *EngineOutput, nodeID string) (T, error) {
+	return engine.GetTyped[T](out, nodeID)
+}
+
 // LLM client aliases
 type (
 	LLMClient     = llm.Client
@@ -113,6 +131,7 @@ type (
 	MetricsCollector  = monitor.MetricsCollector
 	InMemoryCollector = monitor.InMemoryCollector
 	PipelineMetrics   = monitor.PipelineMetrics
+	CostTracker       = monitor.CostTracker
 )
 
 // NewInMemoryCollector creates a new in-memory metrics collector.
@@ -120,10 +139,17 @@ func NewInMemoryCollector(pipelineID string) *InMemoryCollector {
 	return monitor.NewInMemoryCollector(pipelineID)
 }
 
+// NewCostTracker creates a collector that accumulates cost by model and node.
+func NewCostTracker(pipelineID string, prices monitor.PriceTable) *CostTracker {
+	return monitor.NewCostTracker(pipelineID, prices)
+}
+
 // Server aliases
 type (
-	Server       = server.Server
-	ServerConfig = server.Config
+	Server         = server.Server
+	ServerConfig   = server.Config
+	AuthConfig     = server.AuthConfig
+	PostgresConfig = server.PostgresConfig
 )
 
 // NewServer creates a new API server.
@@ -149,6 +175,21 @@ func NewMemoryVectorStore() *vector.MemoryStore {
 }
 
 // NewPgVectorStore creates a new pgvector-based vector store.
-func NewPgVectorStore(dsn string, dimension int) (*vector.PgVectorStore, error) {
-	return vector.NewPgVectorStore(dsn, dimension)
+func NewPgVectorStore(cfg vector.PgVectorConfig) (*vector.PgVectorStore, error) {
+	return vector.NewPgVectorStore(cfg)
+}
+
+// NewQdrantStore creates a new Qdrant-backed vector store.
+func NewQdrantStore(cfg vector.QdrantConfig) (*vector.QdrantStore, error) {
+	return vector.NewQdrantStore(cfg)
+}
+
+// NewPineconeStore creates a new Pinecone-backed vector store.
+func NewPineconeStore(cfg vector.PineconeConfig) (*vector.PineconeStore, error) {
+	return vector.NewPineconeStore(cfg)
+}
+
+// NewHybridStore combines a vector store with BM25 keyword search.
+func NewHybridStore(dense VectorStore, alpha float64) *vector.HybridStore {
+	return vector.NewHybridStore(dense, alpha)
 }
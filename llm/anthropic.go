@@ -17,6 +17,23 @@ type AnthropicClient struct {
 	baseURL string
 	client  *http.Client
 	version string
+
+	// ThinkingBudget enables extended thinking with the given token budget (0 = disabled).
+	ThinkingBudget int
+}
+
+type thinkingBudgetKey struct{}
+
+// WithThinkingBudget overrides the Anthropic extended thinking budget for calls made with ctx.
+func WithThinkingBudget(ctx context.Context, budget int) context.Context {
+	return context.WithValue(ctx, thinkingBudgetKey{}, budget)
+}
+
+func (c *AnthropicClient) thinkingBudget(ctx context.Context) int {
+	if budget, ok := ctx.Value(thinkingBudgetKey{}).(int); ok {
+		return budget
+	}
+	return c.ThinkingBudget
 }
 
 func NewAnthropicClient(apiKey string) *AnthropicClient {
@@ -48,9 +65,10 @@ func (c *AnthropicClient) Chat(ctx context.Context, model string, system, user s
 		return nil, err
 	}
 	return &LLMResponse{
-		Content:      resp.Content,
-		FinishReason: resp.FinishReason,
-		Usage:        resp.Usage,
+		Content:         resp.Content,
+		ThinkingContent: resp.ThinkingContent,
+		FinishReason:    resp.FinishReason,
+		Usage:           resp.Usage,
 	}, nil
 }
 
@@ -77,6 +95,15 @@ func (c *AnthropicClient) ChatWithTools(ctx context.Context, model string, syste
 		reqBody["tools"] = c.buildTools(tools)
 	}
 
+	if budget := c.thinkingBudget(ctx); budget > 0 {
+		reqBody["thinking"] = map[string]any{
+			"type":          "enabled",
+			"budget_tokens": budget,
+		}
+		// max_tokens must exceed the thinking budget
+		reqBody["max_tokens"] = budget + 4096
+	}
+
 	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -184,6 +211,7 @@ func (c *AnthropicClient) parseResponse(resp anthropicResponse) *ChatResponse {
 	blockHandlers := map[string]func(*ChatResponse, anthropicBlock){
 		"text":     c.handleTextBlock,
 		"tool_use": c.handleToolUseBlock,
+		"thinking": c.handleThinkingBlock,
 	}
 
 	for _, block := range resp.Content {
@@ -199,6 +227,10 @@ func (c *AnthropicClient) handleTextBlock(result *ChatResponse, block anthropicB
 	result.Content += block.Text
 }
 
+func (c *AnthropicClient) handleThinkingBlock(result *ChatResponse, block anthropicBlock) {
+	result.ThinkingContent += block.Thinking
+}
+
 func (c *AnthropicClient) handleToolUseBlock(result *ChatResponse, block anthropicBlock) {
 	inputBytes, _ := json.Marshal(block.Input)
 	result.ToolCalls = append(result.ToolCalls, core.ToolCall{
@@ -218,9 +250,10 @@ type anthropicResponse struct {
 }
 
 type anthropicBlock struct {
-	Type  string         `json:"type"`
-	Text  string         `json:"text,omitempty"`
-	ID    string         `json:"id,omitempty"`
-	Name  string         `json:"name,omitempty"`
-	Input map[string]any `json:"input,omitempty"`
+	Type     string         `json:"type"`
+	Text     string         `json:"text,omitempty"`
+	Thinking string         `json:"thinking,omitempty"`
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	Input    map[string]any `json:"input,omitempty"`
 }
@@ -0,0 +1,199 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// BedrockClient calls Anthropic Claude and Meta Llama models through AWS Bedrock InvokeModel.
+// AWS credentials are resolved from the default chain (env, shared config, instance role)
+// on first use.
+type BedrockClient struct {
+	region  string
+	profile string
+
+	once    sync.Once
+	runtime *bedrockruntime.Client
+	initErr error
+
+	// claude formats Claude messages; Bedrock uses the Anthropic Messages schema.
+	claude *AnthropicClient
+}
+
+func NewBedrockClient(region, profile string) *BedrockClient {
+	return &BedrockClient{
+		region:  region,
+		profile: profile,
+		claude:  &AnthropicClient{},
+	}
+}
+
+func (c *BedrockClient) client(ctx context.Context) (*bedrockruntime.Client, error) {
+	c.once.Do(func() {
+		opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(c.region)}
+		if c.profile != "" {
+			opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			c.initErr = fmt.Errorf("load AWS config: %w", err)
+			return
+		}
+		c.runtime = bedrockruntime.NewFromConfig(cfg)
+	})
+	return c.runtime, c.initErr
+}
+
+func (c *BedrockClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	msgs := []core.Message{core.NewUserMessage(user)}
+	resp, err := c.ChatWithTools(ctx, model, system, msgs, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMResponse{
+		Content:         resp.Content,
+		ThinkingContent: resp.ThinkingContent,
+		FinishReason:    resp.FinishReason,
+		Usage:           resp.Usage,
+	}, nil
+}
+
+func (c *BedrockClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	coreMsgs := make([]core.Message, len(msgs))
+	for i, m := range msgs {
+		coreMsgs[i] = core.Message{Role: core.MessageRole(m.Role), Content: m.Content}
+	}
+	return c.ChatWithTools(ctx, model, system, coreMsgs, nil, nil)
+}
+
+func (c *BedrockClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	switch {
+	case isBedrockClaude(model):
+		return c.invokeClaude(ctx, model, system, msgs, tools, pending)
+	case isBedrockLlama(model):
+		if len(tools) > 0 {
+			return nil, fmt.Errorf("tool calls are not supported for Bedrock model: %s", model)
+		}
+		return c.invokeLlama(ctx, model, system, msgs)
+	}
+	return nil, fmt.Errorf("unsupported Bedrock model: %s", model)
+}
+
+func (c *BedrockClient) invokeClaude(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	reqBody := map[string]any{
+		"anthropic_version": "bedrock-2023-05-31",
+		"max_tokens":        4096,
+		"messages":          c.claude.buildMessages(msgs, pending),
+	}
+
+	if system != "" {
+		reqBody["system"] = system
+	}
+
+	if len(tools) > 0 {
+		reqBody["tools"] = c.claude.buildTools(tools)
+	}
+
+	var result anthropicResponse
+	if err := c.invoke(ctx, model, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return c.claude.parseResponse(result), nil
+}
+
+func (c *BedrockClient) invokeLlama(ctx context.Context, model string, system string, msgs []core.Message) (*ChatResponse, error) {
+	reqBody := map[string]any{
+		"prompt":      llamaPrompt(system, msgs),
+		"max_gen_len": 2048,
+	}
+
+	var result llamaResponse
+	if err := c.invoke(ctx, model, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &ChatResponse{
+		Content:      strings.TrimSpace(result.Generation),
+		FinishReason: result.StopReason,
+		Usage: Usage{
+			PromptTokens:     result.PromptTokenCount,
+			CompletionTokens: result.GenerationTokenCount,
+			TotalTokens:      result.PromptTokenCount + result.GenerationTokenCount,
+		},
+	}, nil
+}
+
+func (c *BedrockClient) invoke(ctx context.Context, model string, reqBody map[string]any, out any) error {
+	rt, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := rt.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(model),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("Bedrock invoke failed: %w", err)
+	}
+
+	if err := json.Unmarshal(resp.Body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// llamaPrompt renders messages in the Llama 3 chat template.
+func llamaPrompt(system string, msgs []core.Message) string {
+	var sb strings.Builder
+	sb.WriteString("<|begin_of_text|>")
+
+	writeTurn := func(role, content string) {
+		sb.WriteString("<|start_header_id|>" + role + "<|end_header_id|>\n\n")
+		sb.WriteString(content)
+		sb.WriteString("<|eot_id|>")
+	}
+
+	if system != "" {
+		writeTurn("system", system)
+	}
+	for _, m := range msgs {
+		writeTurn(string(m.Role), m.Content)
+	}
+
+	sb.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+	return sb.String()
+}
+
+type llamaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+	StopReason           string `json:"stop_reason"`
+}
+
+// isBedrockClaude matches Claude model IDs, including cross-region
+// inference profiles such as "us.anthropic.claude-3-5-sonnet-...".
+func isBedrockClaude(model string) bool {
+	return strings.Contains(model, "anthropic.claude")
+}
+
+func isBedrockLlama(model string) bool {
+	return strings.Contains(model, "meta.llama")
+}
@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// CacheConfig configures a CachedClient.
+type CacheConfig struct {
+	TTL        time.Duration // Optional: entry lifetime (0 = never expires)
+	MaxEntries int           // Optional: LRU capacity (0 = unbounded)
+}
+
+// CacheStats reports cache effectiveness.
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+type cacheEntry struct {
+	key       string
+	resp      LLMResponse
+	expiresAt time.Time
+}
+
+// CachedClient wraps a Client and caches Chat responses in memory.
+// Other calls pass through to the wrapped client uncached.
+type CachedClient struct {
+	inner Client
+	cfg   CacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+	stats   CacheStats
+}
+
+var errUnsupported = errors.New("not supported by wrapped client")
+
+func NewCachedClient(inner Client, cfg CacheConfig) *CachedClient {
+	return &CachedClient{
+		inner:   inner,
+		cfg:     cfg,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *CachedClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	key := cacheKey(ctx, model, system, user)
+	if resp, ok := c.get(key); ok {
+		return resp, nil
+	}
+
+	resp, err := c.inner.Chat(ctx, model, system, user)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, *resp)
+	return resp, nil
+}
+
+func (c *CachedClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	return c.inner.ChatWithMessages(ctx, model, system, msgs)
+}
+
+func (c *CachedClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	return c.inner.ChatWithTools(ctx, model, system, msgs, tools, pending)
+}
+
+// ChatWithStructuredOutput passes through to the wrapped client when it supports structured output.
+func (c *CachedClient) ChatWithStructuredOutput(ctx context.Context, model, system, user string, schema json.RawMessage) (*ChatResponse, error) {
+	sc, ok := c.inner.(StructuredClient)
+	if !ok {
+		return nil, fmt.Errorf("structured output %w", errUnsupported)
+	}
+	return sc.ChatWithStructuredOutput(ctx, model, system, user, schema)
+}
+
+// Embed passes through to the wrapped client when it supports embeddings.
+func (c *CachedClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	ec, ok := c.inner.(EmbeddingClient)
+	if !ok {
+		return nil, fmt.Errorf("embeddings %w", errUnsupported)
+	}
+	return ec.Embed(ctx, model, input)
+}
+
+// EmbedBatch passes through to the wrapped client when it supports embeddings.
+func (c *CachedClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	ec, ok := c.inner.(EmbeddingClient)
+	if !ok {
+		return nil, fmt.Errorf("embeddings %w", errUnsupported)
+	}
+	return ec.EmbedBatch(ctx, model, inputs)
+}
+
+// SupportsModel passes through to the wrapped client, assuming support when it cannot tell.
+func (c *CachedClient) SupportsModel(model string) bool {
+	mc, ok := c.inner.(ModelChecker)
+	return !ok || mc.SupportsModel(model)
+}
+
+// Stats returns a snapshot of cache counters.
+func (c *CachedClient) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+func (c *CachedClient) get(key string) (*LLMResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+	resp := entry.resp
+	return &resp, true
+}
+
+func (c *CachedClient) put(key string, resp LLMResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.cfg.TTL > 0 {
+		expiresAt = time.Now().Add(c.cfg.TTL)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = &cacheEntry{key: key, resp: resp, expiresAt: expiresAt}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, resp: resp, expiresAt: expiresAt})
+
+	for c.cfg.MaxEntries > 0 && c.order.Len() > c.cfg.MaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// cacheKey hashes the request; JSON mode is included since it changes the response format.
+func cacheKey(ctx context.Context, model, system, user string) string {
+	h := sha256.New()
+	for _, part := range []string{model, system, user} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	if jsonMode(ctx) {
+		h.Write([]byte("json"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/hubenschmidt/go-fissio/core"
 )
@@ -24,11 +25,61 @@ type EmbeddingClient interface {
 	EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error)
 }
 
+// StructuredClient returns responses constrained to a JSON schema.
+type StructuredClient interface {
+	ChatWithStructuredOutput(ctx context.Context, model, system, user string, schema json.RawMessage) (*ChatResponse, error)
+}
+
+// ModelChecker reports whether a client can route calls for a model.
+type ModelChecker interface {
+	SupportsModel(model string) bool
+}
+
+// BatchEmbeddingClient embeds many inputs with progress reporting.
+type BatchEmbeddingClient interface {
+	EmbeddingClient
+	EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error)
+}
+
+// EmbedBatchOptions controls a batch embedding call.
+type EmbedBatchOptions struct {
+	OnProgress func(done, total int) // Optional: called as inputs complete
+	InputType  string                // Optional: EmbedSearchQuery or EmbedSearchDocument, for providers that distinguish them
+}
+
+// EmbeddingClientConfig configures embedding clients without native batch support.
+type EmbeddingClientConfig struct {
+	BatchConcurrency int // Max concurrent per-input requests (default: 4)
+}
+
+type jsonModeKey struct{}
+
+// WithJSONMode asks providers that support it to constrain responses to a JSON object.
+func WithJSONMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, jsonModeKey{}, true)
+}
+
+func jsonMode(ctx context.Context) bool {
+	enabled, _ := ctx.Value(jsonModeKey{}).(bool)
+	return enabled
+}
+
+type responseSchemaKey struct{}
+
+func withResponseSchema(ctx context.Context, schema json.RawMessage) context.Context {
+	return context.WithValue(ctx, responseSchemaKey{}, schema)
+}
+
+func responseSchema(ctx context.Context) json.RawMessage {
+	schema, _ := ctx.Value(responseSchemaKey{}).(json.RawMessage)
+	return schema
+}
+
 type ClientConfig struct {
-	APIKey      string
-	BaseURL     string
-	Timeout     int
-	MaxRetries  int
+	APIKey       string
+	BaseURL      string
+	Timeout      int
+	MaxRetries   int
 	DefaultModel string
 }
 
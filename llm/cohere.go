@@ -0,0 +1,298 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// Cohere embed input types. Queries and documents are embedded differently,
+// so search quality depends on using the right one for each side.
+const (
+	EmbedSearchQuery    = "search_query"
+	EmbedSearchDocument = "search_document"
+)
+
+type embedInputTypeKey struct{}
+
+// WithEmbedInputType sets the input type for embedding calls made with ctx on
+// providers that distinguish queries from documents (Cohere).
+func WithEmbedInputType(ctx context.Context, inputType string) context.Context {
+	return context.WithValue(ctx, embedInputTypeKey{}, inputType)
+}
+
+func embedInputType(ctx context.Context, opts EmbedBatchOptions) string {
+	if opts.InputType != "" {
+		return opts.InputType
+	}
+	if inputType, ok := ctx.Value(embedInputTypeKey{}).(string); ok && inputType != "" {
+		return inputType
+	}
+	return EmbedSearchDocument
+}
+
+// CohereClient talks to the Cohere v2 chat and embed APIs.
+type CohereClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewCohereClient(apiKey string) *CohereClient {
+	return &CohereClient{
+		apiKey:  apiKey,
+		baseURL: "https://api.cohere.com/v2",
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func NewCohereClientWithConfig(cfg ClientConfig) *CohereClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.cohere.com/v2"
+	}
+	return &CohereClient{
+		apiKey:  cfg.APIKey,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+func (c *CohereClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	msgs := []core.Message{core.NewUserMessage(user)}
+	resp, err := c.ChatWithTools(ctx, model, system, msgs, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMResponse{
+		Content:      resp.Content,
+		FinishReason: resp.FinishReason,
+		Usage:        resp.Usage,
+	}, nil
+}
+
+func (c *CohereClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	coreMsgs := make([]core.Message, len(msgs))
+	for i, m := range msgs {
+		coreMsgs[i] = core.Message{Role: core.MessageRole(m.Role), Content: m.Content}
+	}
+	return c.ChatWithTools(ctx, model, system, coreMsgs, nil, nil)
+}
+
+func (c *CohereClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	reqBody := map[string]any{
+		"model":    model,
+		"messages": c.buildMessages(system, msgs, pending),
+	}
+	if len(tools) > 0 {
+		reqBody["tools"] = c.buildTools(tools)
+	}
+	if jsonMode(ctx) {
+		reqBody["response_format"] = map[string]any{"type": "json_object"}
+	}
+
+	var result cohereChatResponse
+	if err := c.post(ctx, "/chat", reqBody, &result); err != nil {
+		return nil, err
+	}
+	return c.parseResponse(result), nil
+}
+
+func (c *CohereClient) buildMessages(system string, msgs []core.Message, pending []core.ToolResult) []map[string]any {
+	messages := make([]map[string]any, 0, len(msgs)+len(pending)+1)
+
+	if system != "" {
+		messages = append(messages, map[string]any{
+			"role":    "system",
+			"content": system,
+		})
+	}
+
+	for _, m := range msgs {
+		msg := map[string]any{
+			"role":    string(m.Role),
+			"content": m.Content,
+		}
+		if m.ToolCallID != "" {
+			msg["tool_call_id"] = m.ToolCallID
+		}
+		messages = append(messages, msg)
+	}
+
+	for _, p := range pending {
+		messages = append(messages, map[string]any{
+			"role":         "tool",
+			"content":      p.Content,
+			"tool_call_id": p.ToolCallID,
+		})
+	}
+
+	return messages
+}
+
+func (c *CohereClient) buildTools(tools []core.ToolSchema) []map[string]any {
+	result := make([]map[string]any, len(tools))
+	for i, t := range tools {
+		result[i] = map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"parameters":  json.RawMessage(t.Parameters),
+			},
+		}
+	}
+	return result
+}
+
+func (c *CohereClient) parseResponse(resp cohereChatResponse) *ChatResponse {
+	result := &ChatResponse{
+		FinishReason: resp.FinishReason,
+		Usage: Usage{
+			PromptTokens:     int(resp.Usage.Tokens.InputTokens),
+			CompletionTokens: int(resp.Usage.Tokens.OutputTokens),
+			TotalTokens:      int(resp.Usage.Tokens.InputTokens + resp.Usage.Tokens.OutputTokens),
+		},
+	}
+
+	for _, block := range resp.Message.Content {
+		if block.Type == "text" {
+			result.Content += block.Text
+		}
+	}
+
+	// Cohere returns arguments as a JSON string, like OpenAI
+	for _, tc := range resp.Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, core.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: json.RawMessage(tc.Function.Arguments),
+		})
+	}
+	if len(result.ToolCalls) > 0 && result.Content == "" {
+		result.ThinkingContent = resp.Message.ToolPlan
+	}
+
+	return result
+}
+
+// Embed generates an embedding; the input type defaults to search_document
+// unless set with WithEmbedInputType.
+func (c *CohereClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	results, err := c.EmbedBatch(ctx, model, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	return &results[0], nil
+}
+
+func (c *CohereClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	return c.EmbedBatchWithOptions(ctx, model, inputs, EmbedBatchOptions{})
+}
+
+// EmbedBatchWithOptions embeds inputs in one request with opts.InputType.
+func (c *CohereClient) EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error) {
+	reqBody := map[string]any{
+		"model":           model,
+		"texts":           inputs,
+		"input_type":      embedInputType(ctx, opts),
+		"embedding_types": []string{"float"},
+	}
+
+	var result cohereEmbedResponse
+	if err := c.post(ctx, "/embed", reqBody, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Embeddings.Float) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(result.Embeddings.Float))
+	}
+
+	embeddings := make([]EmbeddingResponse, len(inputs))
+	for i, e := range result.Embeddings.Float {
+		embeddings[i] = EmbeddingResponse{Embedding: e}
+	}
+	// Token usage is only reported for the whole batch
+	if len(embeddings) > 0 {
+		embeddings[0].TokenCount = int(result.Meta.BilledUnits.InputTokens)
+	}
+
+	if opts.OnProgress != nil {
+		opts.OnProgress(len(inputs), len(inputs))
+	}
+	return embeddings, nil
+}
+
+func (c *CohereClient) post(ctx context.Context, path string, reqBody any, out any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+type cohereChatResponse struct {
+	FinishReason string `json:"finish_reason"`
+	Message      struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		ToolPlan  string `json:"tool_plan"`
+		ToolCalls []struct {
+			ID       string `json:"id"`
+			Function struct {
+				Name      string `json:"name"`
+				Arguments string `json:"arguments"`
+			} `json:"function"`
+		} `json:"tool_calls"`
+	} `json:"message"`
+	Usage struct {
+		Tokens struct {
+			InputTokens  float64 `json:"input_tokens"`
+			OutputTokens float64 `json:"output_tokens"`
+		} `json:"tokens"`
+	} `json:"usage"`
+}
+
+type cohereEmbedResponse struct {
+	Embeddings struct {
+		Float [][]float64 `json:"float"`
+	} `json:"embeddings"`
+	Meta struct {
+		BilledUnits struct {
+			InputTokens float64 `json:"input_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	contextWindowsMu sync.RWMutex
+	contextWindows   = map[string]int{
+		"gpt-5":             400_000,
+		"gpt-4.5":           128_000,
+		"gpt-4o":            128_000,
+		"gpt-4o-mini":       128_000,
+		"gpt-4.1":           1_047_576,
+		"gpt-4.1-mini":      1_047_576,
+		"gpt-4.1-nano":      1_047_576,
+		"gpt-4-turbo":       128_000,
+		"gpt-4-32k":         32_768,
+		"gpt-4":             8_192,
+		"gpt-3.5-turbo":     16_385,
+		"o1":                200_000,
+		"o1-mini":           128_000,
+		"o3":                200_000,
+		"o3-mini":           200_000,
+		"o4-mini":           200_000,
+		"claude-opus-4":     200_000,
+		"claude-sonnet-4":   200_000,
+		"claude-3-7-sonnet": 200_000,
+		"claude-3-5-sonnet": 200_000,
+		"claude-3-5-haiku":  200_000,
+		"claude-3-opus":     200_000,
+		"claude-3-haiku":    200_000,
+		"llama3":            8_192,
+		"llama3.1":          128_000,
+		"llama3.2":          128_000,
+		"mistral":           32_768,
+		"qwen2.5":           32_768,
+	}
+)
+
+// RegisterModelContextWindow sets the context window, in tokens, reported by
+// ModelContextWindow for model and the models it prefixes.
+func RegisterModelContextWindow(model string, tokens int) {
+	contextWindowsMu.Lock()
+	defer contextWindowsMu.Unlock()
+	contextWindows[model] = tokens
+}
+
+// ModelContextWindow returns model's context window in tokens, or 0 if it is
+// unknown. Like monitor.PriceTable.Lookup it matches exactly first and then by
+// the longest registered name that prefixes model (e.g. dated snapshots or
+// Ollama tags such as "llama3.1:8b").
+func ModelContextWindow(model string) int {
+	contextWindowsMu.RLock()
+	defer contextWindowsMu.RUnlock()
+
+	if tokens, ok := contextWindows[model]; ok {
+		return tokens
+	}
+
+	var best string
+	for name := range contextWindows {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	return contextWindows[best]
+}
@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// EmbeddingCache stores embedding vectors by key.
+type EmbeddingCache interface {
+	Get(key string) ([]float64, bool)
+	Set(key string, v []float64)
+}
+
+type lruEmbeddingEntry struct {
+	key string
+	vec []float64
+}
+
+// LRUEmbeddingCache is an in-memory EmbeddingCache that evicts the least recently used entry.
+type LRUEmbeddingCache struct {
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+}
+
+// NewLRUEmbeddingCache creates a cache holding at most maxSize vectors (0 = unbounded).
+func NewLRUEmbeddingCache(maxSize int) *LRUEmbeddingCache {
+	return &LRUEmbeddingCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *LRUEmbeddingCache) Get(key string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEmbeddingEntry).vec, true
+}
+
+func (c *LRUEmbeddingCache) Set(key string, v []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEmbeddingEntry).vec = v
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEmbeddingEntry{key: key, vec: v})
+
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEmbeddingEntry).key)
+	}
+}
+
+// CachingEmbeddingClient serves repeated embedding requests from an EmbeddingCache.
+type CachingEmbeddingClient struct {
+	inner EmbeddingClient
+	cache EmbeddingCache
+}
+
+// NewCachingEmbeddingClient wraps inner so embeddings are looked up in cache before calling it.
+func NewCachingEmbeddingClient(inner EmbeddingClient, cache EmbeddingCache) EmbeddingClient {
+	return &CachingEmbeddingClient{inner: inner, cache: cache}
+}
+
+func (c *CachingEmbeddingClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	key := embeddingCacheKey(model, input)
+	if vec, ok := c.cache.Get(key); ok {
+		return &EmbeddingResponse{Embedding: vec}, nil
+	}
+
+	resp, err := c.inner.Embed(ctx, model, input)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(key, resp.Embedding)
+	return resp, nil
+}
+
+func (c *CachingEmbeddingClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	return c.EmbedBatchWithOptions(ctx, model, inputs, EmbedBatchOptions{})
+}
+
+// EmbedBatchWithOptions embeds only the inputs missing from the cache.
+// Cached inputs count as done before the inner client reports progress.
+func (c *CachingEmbeddingClient) EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error) {
+	results := make([]EmbeddingResponse, len(inputs))
+	keys := make([]string, len(inputs))
+	var missIdx []int
+	var missInputs []string
+
+	for i, input := range inputs {
+		keys[i] = embeddingCacheKey(model, input)
+		if vec, ok := c.cache.Get(keys[i]); ok {
+			results[i] = EmbeddingResponse{Embedding: vec}
+			continue
+		}
+		missIdx = append(missIdx, i)
+		missInputs = append(missInputs, input)
+	}
+
+	hits := len(inputs) - len(missInputs)
+	if opts.OnProgress != nil && hits > 0 {
+		opts.OnProgress(hits, len(inputs))
+	}
+	if len(missInputs) == 0 {
+		return results, nil
+	}
+
+	innerOpts := opts
+	if opts.OnProgress != nil {
+		innerOpts.OnProgress = func(done, _ int) { opts.OnProgress(hits+done, len(inputs)) }
+	}
+
+	var fetched []EmbeddingResponse
+	var err error
+	if bc, ok := c.inner.(BatchEmbeddingClient); ok {
+		fetched, err = bc.EmbedBatchWithOptions(ctx, model, missInputs, innerOpts)
+	} else {
+		fetched, err = c.inner.EmbedBatch(ctx, model, missInputs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for j, i := range missIdx {
+		results[i] = fetched[j]
+		c.cache.Set(keys[i], fetched[j].Embedding)
+	}
+	return results, nil
+}
+
+func embeddingCacheKey(model, input string) string {
+	sum := sha256.Sum256([]byte(model + "|" + input))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FallbackConfig describes a primary model and the models to try, in order,
+// when a call fails with a retryable error.
+type FallbackConfig struct {
+	Primary         string
+	Fallbacks       []string
+	RetryableErrors []string // Optional: error substrings that trigger failover (default: DefaultRetryableErrors)
+}
+
+// DefaultRetryableErrors matches rate limiting and provider availability failures.
+var DefaultRetryableErrors = []string{
+	"status 429",
+	"status 500",
+	"status 502",
+	"status 503",
+	"status 504",
+	"status 529",
+	"rate limit",
+	"overloaded",
+	"timeout",
+	"connection refused",
+}
+
+func (c FallbackConfig) retryable(err error) bool {
+	patterns := c.RetryableErrors
+	if len(patterns) == 0 {
+		patterns = DefaultRetryableErrors
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range patterns {
+		if strings.Contains(msg, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChatWithFallback tries cfg.Primary and then each fallback on retryable errors.
+// It returns the response and the model that produced it.
+func ChatWithFallback(ctx context.Context, client Client, cfg FallbackConfig, system, user string) (*LLMResponse, string, error) {
+	models := append([]string{cfg.Primary}, cfg.Fallbacks...)
+
+	var errs []error
+	for _, model := range models {
+		resp, err := client.Chat(ctx, model, system, user)
+		if err == nil {
+			return resp, model, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", model, err))
+		if ctx.Err() != nil || !cfg.retryable(err) {
+			break
+		}
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// ChatWithFallback tries cfg.Primary and then each fallback on retryable errors.
+// It returns the response and the model that produced it.
+func (u *UnifiedClient) ChatWithFallback(ctx context.Context, cfg FallbackConfig, system, user string) (*LLMResponse, string, error) {
+	return ChatWithFallback(ctx, u, cfg, system, user)
+}
@@ -0,0 +1,222 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// HuggingFaceClient talks to the HuggingFace Inference API. Chat uses each
+// model's OpenAI-compatible chat-completion route; embeddings use the
+// feature-extraction task on the model endpoint.
+type HuggingFaceClient struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewHuggingFaceClient(token string) *HuggingFaceClient {
+	return &HuggingFaceClient{
+		token:   token,
+		baseURL: "https://api-inference.huggingface.co/models",
+		client:  &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+func NewHuggingFaceClientWithConfig(cfg ClientConfig) *HuggingFaceClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api-inference.huggingface.co/models"
+	}
+	return &HuggingFaceClient{
+		token:   cfg.APIKey,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+func (c *HuggingFaceClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	msgs := []core.Message{core.NewUserMessage(user)}
+	resp, err := c.ChatWithTools(ctx, model, system, msgs, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMResponse{
+		Content:      resp.Content,
+		FinishReason: resp.FinishReason,
+		Usage:        resp.Usage,
+	}, nil
+}
+
+func (c *HuggingFaceClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	coreMsgs := make([]core.Message, len(msgs))
+	for i, m := range msgs {
+		coreMsgs[i] = core.Message{Role: core.MessageRole(m.Role), Content: m.Content}
+	}
+	return c.ChatWithTools(ctx, model, system, coreMsgs, nil, nil)
+}
+
+func (c *HuggingFaceClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	reqBody := map[string]any{
+		"model":    model,
+		"messages": c.buildMessages(system, msgs, pending),
+	}
+	if len(tools) > 0 {
+		reqBody["tools"] = c.buildTools(tools)
+	}
+	if jsonMode(ctx) {
+		reqBody["response_format"] = map[string]any{"type": "json_object"}
+	}
+
+	var result openAIResponse
+	if err := c.post(ctx, "/"+model+"/v1/chat/completions", reqBody, &result); err != nil {
+		return nil, err
+	}
+	return c.parseResponse(result), nil
+}
+
+func (c *HuggingFaceClient) buildMessages(system string, msgs []core.Message, pending []core.ToolResult) []map[string]any {
+	messages := make([]map[string]any, 0, len(msgs)+len(pending)+1)
+
+	if system != "" {
+		messages = append(messages, map[string]any{
+			"role":    "system",
+			"content": system,
+		})
+	}
+
+	for _, m := range msgs {
+		msg := map[string]any{
+			"role":    string(m.Role),
+			"content": m.Content,
+		}
+		if m.ToolCallID != "" {
+			msg["tool_call_id"] = m.ToolCallID
+		}
+		messages = append(messages, msg)
+	}
+
+	for _, p := range pending {
+		messages = append(messages, map[string]any{
+			"role":         "tool",
+			"content":      p.Content,
+			"tool_call_id": p.ToolCallID,
+		})
+	}
+
+	return messages
+}
+
+func (c *HuggingFaceClient) buildTools(tools []core.ToolSchema) []map[string]any {
+	result := make([]map[string]any, len(tools))
+	for i, t := range tools {
+		result[i] = map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"parameters":  json.RawMessage(t.Parameters),
+			},
+		}
+	}
+	return result
+}
+
+func (c *HuggingFaceClient) parseResponse(resp openAIResponse) *ChatResponse {
+	if len(resp.Choices) == 0 {
+		return &ChatResponse{}
+	}
+
+	choice := resp.Choices[0]
+	result := &ChatResponse{
+		Content:      choice.Message.Content,
+		FinishReason: choice.FinishReason,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+
+	for _, tc := range choice.Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, core.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: json.RawMessage(tc.Function.Arguments),
+		})
+	}
+
+	return result
+}
+
+// Embed generates an embedding with the model's feature-extraction task.
+func (c *HuggingFaceClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	results, err := c.EmbedBatch(ctx, model, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	return &results[0], nil
+}
+
+// EmbedBatch embeds inputs in one feature-extraction request. The model must
+// pool its output to one vector per input, as sentence-transformers models do.
+func (c *HuggingFaceClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	reqBody := map[string]any{
+		"inputs":  inputs,
+		"options": map[string]any{"wait_for_model": true},
+	}
+
+	var result [][]float64
+	if err := c.post(ctx, "/"+model, reqBody, &result); err != nil {
+		return nil, err
+	}
+	if len(result) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(result))
+	}
+
+	embeddings := make([]EmbeddingResponse, len(result))
+	for i, e := range result {
+		embeddings[i] = EmbeddingResponse{Embedding: e}
+	}
+	return embeddings, nil
+}
+
+func (c *HuggingFaceClient) post(ctx context.Context, path string, reqBody any, out any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// ErrNoMockResponse is returned by MockClient when no response matches a
+// call and no default is set.
+var ErrNoMockResponse = errors.New("no mock response matches call")
+
+// MockResponse is a canned reply. Empty match fields match anything.
+type MockResponse struct {
+	Model        string // Matches models with this prefix
+	UserContains string // Matches calls whose user message contains this text
+	Response     ChatResponse
+}
+
+// RecordedCall is one invocation of a MockClient method.
+type RecordedCall struct {
+	Method   string
+	Model    string
+	System   string
+	User     string
+	Messages []core.Message
+	Tools    []core.ToolSchema
+	Inputs   []string // Embedding inputs
+}
+
+// MockClient is a deterministic Client, StreamClient and EmbeddingClient
+// for tests. Responses are tried in order and the first match wins.
+type MockClient struct {
+	responses []MockResponse
+
+	Default      *ChatResponse // Optional: returned when nothing matches (default: ErrNoMockResponse)
+	EmbeddingDim int           // Dimension of mock embeddings (default: 8)
+
+	mu    sync.Mutex
+	calls []RecordedCall
+}
+
+func NewMockClient(responses []MockResponse) *MockClient {
+	return &MockClient{
+		responses:    responses,
+		EmbeddingDim: 8,
+	}
+}
+
+// Calls returns every recorded invocation in order.
+func (m *MockClient) Calls() []RecordedCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RecordedCall(nil), m.calls...)
+}
+
+// Reset clears the recorded calls.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *MockClient) record(call RecordedCall) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+func (m *MockClient) match(model, user string) (*ChatResponse, error) {
+	for _, r := range m.responses {
+		if strings.HasPrefix(model, r.Model) && strings.Contains(user, r.UserContains) {
+			resp := r.Response
+			return &resp, nil
+		}
+	}
+	if m.Default != nil {
+		resp := *m.Default
+		return &resp, nil
+	}
+	return nil, fmt.Errorf("%w: model %q", ErrNoMockResponse, model)
+}
+
+func (m *MockClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	m.record(RecordedCall{Method: "Chat", Model: model, System: system, User: user})
+	resp, err := m.match(model, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMResponse{
+		Content:         resp.Content,
+		ThinkingContent: resp.ThinkingContent,
+		FinishReason:    resp.FinishReason,
+		Usage:           resp.Usage,
+	}, nil
+}
+
+func (m *MockClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	coreMsgs := make([]core.Message, len(msgs))
+	for i, msg := range msgs {
+		coreMsgs[i] = core.Message{Role: core.MessageRole(msg.Role), Content: msg.Content}
+	}
+	user := lastUserContent(coreMsgs)
+	m.record(RecordedCall{Method: "ChatWithMessages", Model: model, System: system, User: user, Messages: coreMsgs})
+	return m.match(model, user)
+}
+
+func (m *MockClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	user := lastUserContent(msgs)
+	m.record(RecordedCall{Method: "ChatWithTools", Model: model, System: system, User: user, Messages: msgs, Tools: tools})
+	return m.match(model, user)
+}
+
+func (m *MockClient) ChatStream(ctx context.Context, model string, system, user string) (<-chan StreamChunk, error) {
+	return m.ChatStreamWithMessages(ctx, model, system, []Message{{Role: "user", Content: user}})
+}
+
+// ChatStreamWithMessages streams the matched content one word per chunk.
+func (m *MockClient) ChatStreamWithMessages(ctx context.Context, model string, system string, msgs []Message) (<-chan StreamChunk, error) {
+	coreMsgs := make([]core.Message, len(msgs))
+	for i, msg := range msgs {
+		coreMsgs[i] = core.Message{Role: core.MessageRole(msg.Role), Content: msg.Content}
+	}
+	user := lastUserContent(coreMsgs)
+	m.record(RecordedCall{Method: "ChatStreamWithMessages", Model: model, System: system, User: user, Messages: coreMsgs})
+
+	resp, err := m.match(model, user)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.SplitAfter(resp.Content, " ")
+	ch := make(chan StreamChunk, len(words)+1)
+	go func() {
+		defer close(ch)
+		for _, w := range words {
+			if w == "" {
+				continue
+			}
+			select {
+			case ch <- StreamChunk{Content: w}:
+			case <-ctx.Done():
+				ch <- StreamChunk{Error: ctx.Err(), Done: true}
+				return
+			}
+		}
+		usage := resp.Usage
+		ch <- StreamChunk{ToolCalls: resp.ToolCalls, Usage: &usage, Done: true}
+	}()
+	return ch, nil
+}
+
+func (m *MockClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	m.record(RecordedCall{Method: "Embed", Model: model, Inputs: []string{input}})
+	return &EmbeddingResponse{Embedding: m.embedding(input), TokenCount: len(strings.Fields(input))}, nil
+}
+
+func (m *MockClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	m.record(RecordedCall{Method: "EmbedBatch", Model: model, Inputs: inputs})
+	results := make([]EmbeddingResponse, len(inputs))
+	for i, input := range inputs {
+		results[i] = EmbeddingResponse{Embedding: m.embedding(input), TokenCount: len(strings.Fields(input))}
+	}
+	return results, nil
+}
+
+// embedding derives a unit vector from a hash of input, so equal inputs
+// always embed identically.
+func (m *MockClient) embedding(input string) []float64 {
+	dim := m.EmbeddingDim
+	if dim <= 0 {
+		dim = 8
+	}
+
+	vec := make([]float64, dim)
+	var norm float64
+	for i := range vec {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d:%s", i, input)
+		vec[i] = float64(h.Sum64()%2000)/1000 - 1
+		norm += vec[i] * vec[i]
+	}
+	if norm = math.Sqrt(norm); norm > 0 {
+		for i := range vec {
+			vec[i] /= norm
+		}
+	}
+	return vec
+}
+
+func lastUserContent(msgs []core.Message) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == core.RoleUser {
+			return msgs[i].Content
+		}
+	}
+	return ""
+}
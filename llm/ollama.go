@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -90,83 +91,148 @@ func formatDisplayName(name string) string {
 
 // OllamaEmbedClient handles Ollama-native embedding API.
 type OllamaEmbedClient struct {
-	baseURL string
-	client  *http.Client
+	baseURL     string
+	client      *http.Client
+	concurrency int
 }
 
 // NewOllamaEmbedClient creates a client for Ollama's native embedding API.
 func NewOllamaEmbedClient(baseURL string) *OllamaEmbedClient {
+	return NewOllamaEmbedClientWithConfig(baseURL, EmbeddingClientConfig{})
+}
+
+// NewOllamaEmbedClientWithConfig creates an Ollama embedding client with batch settings.
+func NewOllamaEmbedClientWithConfig(baseURL string, cfg EmbeddingClientConfig) *OllamaEmbedClient {
 	host := strings.TrimSuffix(baseURL, "/")
 	host = strings.TrimSuffix(host, "/v1")
+
+	concurrency := cfg.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
 	return &OllamaEmbedClient{
-		baseURL: host,
-		client:  &http.Client{Timeout: 60 * time.Second},
+		baseURL:     host,
+		client:      &http.Client{Timeout: 60 * time.Second},
+		concurrency: concurrency,
 	}
 }
 
 // Embed generates an embedding for a single input using Ollama's native API.
 func (c *OllamaEmbedClient) Embed(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
-	results, err := c.EmbedBatch(ctx, model, []string{input})
-	if err != nil {
-		return nil, err
-	}
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no embedding returned")
-	}
-	return &results[0], nil
+	return c.embedOne(ctx, model, input)
 }
 
 // EmbedBatch generates embeddings for multiple inputs using Ollama's native API.
 func (c *OllamaEmbedClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
-	results := make([]EmbeddingResponse, 0, len(inputs))
+	return c.EmbedBatchWithOptions(ctx, model, inputs, EmbedBatchOptions{})
+}
 
-	// Ollama's /api/embed endpoint processes one input at a time
-	for _, input := range inputs {
-		reqBody := map[string]any{
-			"model": model,
-			"input": input,
-		}
+// EmbedBatchWithOptions embeds inputs using a pool of concurrent per-input
+// requests, since Ollama's /api/embed is called once per input. The first
+// failure cancels outstanding requests.
+func (c *OllamaEmbedClient) EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		body, err := json.Marshal(reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request: %w", err)
-		}
+	results := make([]EmbeddingResponse, len(inputs))
+	jobs := make(chan int)
+
+	var (
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	workers := min(c.concurrency, len(inputs))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := c.embedOne(ctx, model, inputs[i])
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("input %d: %w", i, err)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				results[i] = *resp
+				done++
+				if opts.OnProgress != nil {
+					opts.OnProgress(done, len(inputs))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/embed", bytes.NewReader(body))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+feed:
+	for i := range inputs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
 		}
-		req.Header.Set("Content-Type", "application/json")
+	}
+	close(jobs)
+	wg.Wait()
 
-		resp, err := c.client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("request failed: %w", err)
-		}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			respBody, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("Ollama API error (status %d): %s", resp.StatusCode, string(respBody))
-		}
+func (c *OllamaEmbedClient) embedOne(ctx context.Context, model, input string) (*EmbeddingResponse, error) {
+	reqBody := map[string]any{
+		"model": model,
+		"input": input,
+	}
 
-		var result ollamaEmbedResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-		resp.Body.Close()
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
 
-		if len(result.Embeddings) == 0 {
-			return nil, fmt.Errorf("no embeddings in response")
-		}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-		results = append(results, EmbeddingResponse{
-			Embedding:  result.Embeddings[0],
-			TokenCount: 0, // Ollama doesn't report token counts
-		})
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return results, nil
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings in response")
+	}
+
+	return &EmbeddingResponse{
+		Embedding:  result.Embeddings[0],
+		TokenCount: 0, // Ollama doesn't report token counts
+	}, nil
 }
 
 type ollamaEmbedResponse struct {
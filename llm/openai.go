@@ -17,9 +17,46 @@ import (
 type OpenAIClient struct {
 	apiKey  string
 	baseURL string
+	headers map[string]string // extra headers, set by OpenAICompatClient
 	client  *http.Client
 }
 
+type reasoningEffortKey struct{}
+
+// WithReasoningEffort sets reasoning_effort ("low", "medium", "high") for
+// OpenAI reasoning model calls made with ctx. Other models ignore it.
+func WithReasoningEffort(ctx context.Context, effort string) context.Context {
+	return context.WithValue(ctx, reasoningEffortKey{}, effort)
+}
+
+// isReasoningModel reports whether model is an o1/o3 reasoning model.
+func isReasoningModel(model string) bool {
+	for _, family := range []string{"o1", "o3"} {
+		if model == family || strings.HasPrefix(model, family+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyReasoningFormat adapts a chat request for reasoning models, which reject
+// system messages (they take developer messages instead) and accept reasoning_effort.
+func applyReasoningFormat(ctx context.Context, model string, reqBody map[string]any) {
+	if !isReasoningModel(model) {
+		return
+	}
+	if messages, ok := reqBody["messages"].([]map[string]any); ok {
+		for _, m := range messages {
+			if m["role"] == "system" {
+				m["role"] = "developer"
+			}
+		}
+	}
+	if effort, _ := ctx.Value(reasoningEffortKey{}).(string); effort != "" {
+		reqBody["reasoning_effort"] = effort
+	}
+}
+
 func NewOpenAIClient(apiKey string) *OpenAIClient {
 	return &OpenAIClient{
 		apiKey:  apiKey,
@@ -40,6 +77,12 @@ func NewOpenAIClientWithConfig(cfg ClientConfig) *OpenAIClient {
 	}
 }
 
+func (c *OpenAIClient) setHeaders(req *http.Request) {
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+}
+
 func (c *OpenAIClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
 	msgs := []core.Message{
 		core.NewSystemMessage(system),
@@ -56,6 +99,13 @@ func (c *OpenAIClient) Chat(ctx context.Context, model string, system, user stri
 	}, nil
 }
 
+// ChatWithStructuredOutput constrains the response to the given JSON schema
+// using OpenAI structured outputs in strict mode.
+func (c *OpenAIClient) ChatWithStructuredOutput(ctx context.Context, model, system, user string, schema json.RawMessage) (*ChatResponse, error) {
+	msgs := []core.Message{core.NewUserMessage(user)}
+	return c.ChatWithTools(withResponseSchema(ctx, schema), model, system, msgs, nil, nil)
+}
+
 func (c *OpenAIClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
 	coreMsgs := make([]core.Message, len(msgs))
 	for i, m := range msgs {
@@ -76,6 +126,21 @@ func (c *OpenAIClient) ChatWithTools(ctx context.Context, model string, system s
 		reqBody["tools"] = c.buildTools(tools)
 	}
 
+	applyReasoningFormat(ctx, model, reqBody)
+
+	if schema := responseSchema(ctx); schema != nil {
+		reqBody["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "response",
+				"schema": schema,
+				"strict": true,
+			},
+		}
+	} else if jsonMode(ctx) {
+		reqBody["response_format"] = map[string]any{"type": "json_object"}
+	}
+
 	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -88,6 +153,7 @@ func (c *OpenAIClient) ChatWithTools(ctx context.Context, model string, system s
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setHeaders(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -123,6 +189,9 @@ func (c *OpenAIClient) buildMessages(system string, msgs []core.Message, pending
 			"role":    string(m.Role),
 			"content": m.Content,
 		}
+		if len(m.Parts) > 0 {
+			msg["content"] = m.Parts
+		}
 		if m.ToolCallID != "" {
 			msg["tool_call_id"] = m.ToolCallID
 		}
@@ -211,6 +280,10 @@ type openAIToolCall struct {
 	} `json:"function"`
 }
 
+func (c *OpenAIClient) ChatStream(ctx context.Context, model string, system, user string) (<-chan StreamChunk, error) {
+	return c.ChatStreamWithMessages(ctx, model, system, []Message{{Role: "user", Content: user}})
+}
+
 func (c *OpenAIClient) ChatStreamWithMessages(ctx context.Context, model string, system string, msgs []Message) (<-chan StreamChunk, error) {
 	coreMsgs := make([]core.Message, len(msgs))
 	for i, m := range msgs {
@@ -224,6 +297,7 @@ func (c *OpenAIClient) ChatStreamWithMessages(ctx context.Context, model string,
 		"messages": messages,
 		"stream":   true,
 	}
+	applyReasoningFormat(ctx, model, reqBody)
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
@@ -237,6 +311,7 @@ func (c *OpenAIClient) ChatStreamWithMessages(ctx context.Context, model string,
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setHeaders(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -324,8 +399,14 @@ func (c *OpenAIClient) Embed(ctx context.Context, model, input string) (*Embeddi
 	return &results[0], nil
 }
 
-// EmbedBatch generates embeddings for multiple inputs.
+// EmbedBatch generates embeddings for multiple inputs in a single request.
 func (c *OpenAIClient) EmbedBatch(ctx context.Context, model string, inputs []string) ([]EmbeddingResponse, error) {
+	return c.EmbedBatchWithOptions(ctx, model, inputs, EmbedBatchOptions{})
+}
+
+// EmbedBatchWithOptions generates embeddings for multiple inputs in a single request,
+// reporting progress once the batch completes.
+func (c *OpenAIClient) EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error) {
 	reqBody := map[string]any{
 		"model": model,
 		"input": inputs,
@@ -345,6 +426,7 @@ func (c *OpenAIClient) EmbedBatch(ctx context.Context, model string, inputs []st
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
+	c.setHeaders(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -362,14 +444,20 @@ func (c *OpenAIClient) EmbedBatch(ctx context.Context, model string, inputs []st
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	embeddings := make([]EmbeddingResponse, len(result.Data))
-	for i, d := range result.Data {
-		embeddings[i] = EmbeddingResponse{
+	embeddings := make([]EmbeddingResponse, len(inputs))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(inputs) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		embeddings[d.Index] = EmbeddingResponse{
 			Embedding:  d.Embedding,
 			TokenCount: result.Usage.PromptTokens / len(inputs), // approximate per-input tokens
 		}
 	}
 
+	if opts.OnProgress != nil {
+		opts.OnProgress(len(inputs), len(inputs))
+	}
 	return embeddings, nil
 }
 
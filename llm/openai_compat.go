@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"net/http"
+	"time"
+)
+
+// OpenRouterBaseURL is OpenRouter's OpenAI-compatible API endpoint.
+const OpenRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// OpenAICompatConfig configures an OpenAICompatClient.
+type OpenAICompatConfig struct {
+	BaseURL string
+	APIKey  string
+	Headers map[string]string // Optional: extra headers sent with every request
+	Timeout int               // Optional: request timeout in seconds (default: 60)
+}
+
+// OpenAICompatClient calls any API that speaks the OpenAI chat completions
+// protocol, such as OpenRouter, with its own base URL and headers.
+type OpenAICompatClient struct {
+	*OpenAIClient
+}
+
+func NewOpenAICompatClient(cfg OpenAICompatConfig) *OpenAICompatClient {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60
+	}
+	return &OpenAICompatClient{&OpenAIClient{
+		apiKey:  cfg.APIKey,
+		baseURL: cfg.BaseURL,
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}}
+}
+
+// NewOpenRouterClient creates a client for OpenRouter. referer and title are
+// optional and sent as the HTTP-Referer and X-Title attribution headers.
+func NewOpenRouterClient(apiKey, referer, title string) *OpenAICompatClient {
+	headers := make(map[string]string)
+	if referer != "" {
+		headers["HTTP-Referer"] = referer
+	}
+	if title != "" {
+		headers["X-Title"] = title
+	}
+	return NewOpenAICompatClient(OpenAICompatConfig{
+		BaseURL: OpenRouterBaseURL,
+		APIKey:  apiKey,
+		Headers: headers,
+	})
+}
@@ -8,9 +8,10 @@ type Message struct {
 }
 
 type LLMResponse struct {
-	Content      string `json:"content"`
-	FinishReason string `json:"finish_reason,omitempty"`
-	Usage        Usage  `json:"usage,omitempty"`
+	Content         string `json:"content"`
+	ThinkingContent string `json:"thinking_content,omitempty"`
+	FinishReason    string `json:"finish_reason,omitempty"`
+	Usage           Usage  `json:"usage,omitempty"`
 }
 
 type Usage struct {
@@ -20,10 +21,11 @@ type Usage struct {
 }
 
 type ChatResponse struct {
-	Content      string          `json:"content"`
-	ToolCalls    []core.ToolCall `json:"tool_calls,omitempty"`
-	FinishReason string          `json:"finish_reason,omitempty"`
-	Usage        Usage           `json:"usage,omitempty"`
+	Content         string          `json:"content"`
+	ThinkingContent string          `json:"thinking_content,omitempty"`
+	ToolCalls       []core.ToolCall `json:"tool_calls,omitempty"`
+	FinishReason    string          `json:"finish_reason,omitempty"`
+	Usage           Usage           `json:"usage,omitempty"`
 }
 
 type StreamChunk struct {
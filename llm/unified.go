@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -13,16 +14,52 @@ type UnifiedClient struct {
 	anthropic   *AnthropicClient
 	ollama      *OpenAIClient
 	ollamaEmbed *OllamaEmbedClient
+	bedrock     *BedrockClient
+	cohere      *CohereClient
+	huggingface *HuggingFaceClient
+	openrouter  *OpenAICompatClient
+	limits      map[string]chan struct{}
+	embedCache  EmbeddingCache
+	cohereKey   string
 }
 
 type UnifiedConfig struct {
 	OpenAIKey    string
 	AnthropicKey string
 	OllamaURL    string
+
+	AnthropicThinkingBudget int // Extended thinking budget for Claude models (0 = disabled)
+
+	AWSRegion  string // Optional: enables AWS Bedrock for "bedrock/" models
+	AWSProfile string // Optional: shared config profile for Bedrock credentials
+
+	Concurrency map[string]int // Optional: model name prefix -> max in-flight calls
+
+	Embedding EmbeddingClientConfig // Optional: batch settings for Ollama embeddings
+
+	EmbeddingCache EmbeddingCache // Optional: reuse embeddings for repeated inputs
+
+	CohereKey string // Optional: enables Cohere "command-" chat, "embed-" embeddings and search reranking
+
+	HuggingFaceKey string // Optional: enables HuggingFace Inference for "hf/" models
+
+	OpenRouterKey     string // Optional: enables OpenRouter for "openrouter/" models, e.g. "openrouter/meta-llama/llama-3.1-70b-instruct"
+	OpenRouterReferer string // Optional: HTTP-Referer attribution header for OpenRouter
+	OpenRouterTitle   string // Optional: X-Title attribution header for OpenRouter
 }
 
 func NewUnifiedClient(cfg UnifiedConfig) *UnifiedClient {
-	u := &UnifiedClient{}
+	u := &UnifiedClient{
+		limits:     make(map[string]chan struct{}),
+		embedCache: cfg.EmbeddingCache,
+		cohereKey:  cfg.CohereKey,
+	}
+
+	for prefix, n := range cfg.Concurrency {
+		if n > 0 {
+			u.limits[prefix] = make(chan struct{}, n)
+		}
+	}
 
 	if cfg.OpenAIKey != "" {
 		u.openai = NewOpenAIClient(cfg.OpenAIKey)
@@ -30,37 +67,103 @@ func NewUnifiedClient(cfg UnifiedConfig) *UnifiedClient {
 
 	if cfg.AnthropicKey != "" {
 		u.anthropic = NewAnthropicClient(cfg.AnthropicKey)
+		u.anthropic.ThinkingBudget = cfg.AnthropicThinkingBudget
+	}
+
+	if cfg.CohereKey != "" {
+		u.cohere = NewCohereClient(cfg.CohereKey)
+	}
+
+	if cfg.HuggingFaceKey != "" {
+		u.huggingface = NewHuggingFaceClient(cfg.HuggingFaceKey)
+	}
+
+	if cfg.OpenRouterKey != "" {
+		u.openrouter = NewOpenRouterClient(cfg.OpenRouterKey, cfg.OpenRouterReferer, cfg.OpenRouterTitle)
+	}
+
+	if cfg.AWSRegion != "" {
+		u.bedrock = NewBedrockClient(cfg.AWSRegion, cfg.AWSProfile)
 	}
 
 	if cfg.OllamaURL != "" {
 		u.ollama = NewOpenAIClientWithConfig(ClientConfig{
 			BaseURL: cfg.OllamaURL,
 		})
-		u.ollamaEmbed = NewOllamaEmbedClient(cfg.OllamaURL)
+		u.ollamaEmbed = NewOllamaEmbedClientWithConfig(cfg.OllamaURL, cfg.Embedding)
 	}
 
 	return u
 }
 
+// CohereKey returns the configured Cohere API key, or "" if none.
+func (u *UnifiedClient) CohereKey() string {
+	return u.cohereKey
+}
+
 func (u *UnifiedClient) Chat(ctx context.Context, model string, system, user string) (*LLMResponse, error) {
+	release, err := u.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	client, resolvedModel := u.resolveClient(model)
 	return client.Chat(ctx, resolvedModel, system, user)
 }
 
 func (u *UnifiedClient) ChatWithMessages(ctx context.Context, model string, system string, msgs []Message) (*ChatResponse, error) {
+	release, err := u.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	client, resolvedModel := u.resolveClient(model)
 	return client.ChatWithMessages(ctx, resolvedModel, system, msgs)
 }
 
+func (u *UnifiedClient) ChatStream(ctx context.Context, model string, system, user string) (<-chan StreamChunk, error) {
+	return u.ChatStreamWithMessages(ctx, model, system, []Message{{Role: "user", Content: user}})
+}
+
+// ChatStreamWithMessages holds the model's concurrency slot until the
+// returned channel is closed.
 func (u *UnifiedClient) ChatStreamWithMessages(ctx context.Context, model string, system string, msgs []Message) (<-chan StreamChunk, error) {
+	release, err := u.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
 	client, resolvedModel := u.resolveClient(model)
-	if sc, ok := client.(*OpenAIClient); ok {
-		return sc.ChatStreamWithMessages(ctx, resolvedModel, system, msgs)
+	if sc, ok := client.(StreamClient); ok {
+		stream, err := sc.ChatStreamWithMessages(ctx, resolvedModel, system, msgs)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		ch := make(chan StreamChunk)
+		go func() {
+			defer close(ch)
+			defer release()
+			for chunk := range stream {
+				select {
+				case ch <- chunk:
+				case <-ctx.Done():
+					// The request is cancelled too, so the provider's stream ends soon.
+					for range stream {
+					}
+					return
+				}
+			}
+		}()
+		return ch, nil
 	}
 	// Fallback: non-streaming response wrapped in channel
 	ch := make(chan StreamChunk, 1)
 	go func() {
 		defer close(ch)
+		defer release()
 		resp, err := client.ChatWithMessages(ctx, resolvedModel, system, msgs)
 		if err != nil {
 			ch <- StreamChunk{Error: err, Done: true}
@@ -73,24 +176,77 @@ func (u *UnifiedClient) ChatStreamWithMessages(ctx context.Context, model string
 }
 
 func (u *UnifiedClient) ChatWithTools(ctx context.Context, model string, system string, msgs []core.Message, tools []core.ToolSchema, pending []core.ToolResult) (*ChatResponse, error) {
+	release, err := u.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	client, resolvedModel := u.resolveClient(model)
 	return client.ChatWithTools(ctx, resolvedModel, system, msgs, tools, pending)
 }
 
+// ChatWithStructuredOutput routes to an OpenAI-compatible client for schema-constrained output.
+func (u *UnifiedClient) ChatWithStructuredOutput(ctx context.Context, model, system, user string, schema json.RawMessage) (*ChatResponse, error) {
+	release, err := u.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	client, resolvedModel := u.resolveClient(model)
+	sc, ok := client.(StructuredClient)
+	if !ok {
+		return nil, fmt.Errorf("structured output not supported for model: %s", model)
+	}
+	return sc.ChatWithStructuredOutput(ctx, resolvedModel, system, user, schema)
+}
+
+// acquire takes a slot from the semaphore of the longest matching
+// Concurrency prefix, blocking until one is free or ctx is done.
+func (u *UnifiedClient) acquire(ctx context.Context, model string) (func(), error) {
+	var sem chan struct{}
+	var best string
+	for prefix, ch := range u.limits {
+		if strings.HasPrefix(model, prefix) && len(prefix) >= len(best) {
+			best, sem = prefix, ch
+		}
+	}
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for %s concurrency slot: %w", model, ctx.Err())
+	}
+}
+
+// resolveClient picks the provider for model by prefix. A provider whose
+// concrete client is nil is unconfigured and skipped; it must not be stored
+// in the Client interface, where a nil pointer would no longer compare nil.
 func (u *UnifiedClient) resolveClient(model string) (Client, string) {
 	prefixes := []struct {
-		prefix string
-		client Client
-		strip  bool
+		prefix     string
+		client     Client
+		configured bool
+		strip      bool
 	}{
-		{"claude-", u.anthropic, false},
-		{"gpt-", u.openai, false},
-		{"o1-", u.openai, false},
-		{"ollama/", u.ollama, true},
+		{"claude-", u.anthropic, u.anthropic != nil, false},
+		{"gpt-", u.openai, u.openai != nil, false},
+		{"o1-", u.openai, u.openai != nil, false},
+		{"o3-", u.openai, u.openai != nil, false},
+		{"ollama/", u.ollama, u.ollama != nil, true},
+		{"bedrock/", u.bedrock, u.bedrock != nil, true},
+		{"command-", u.cohere, u.cohere != nil, false},
+		{"hf/", u.huggingface, u.huggingface != nil, true},
+		{"openrouter/", u.openrouter, u.openrouter != nil, true},
 	}
 
 	for _, p := range prefixes {
-		if strings.HasPrefix(model, p.prefix) && p.client != nil {
+		if strings.HasPrefix(model, p.prefix) && p.configured {
 			resolvedModel := model
 			if p.strip {
 				resolvedModel = strings.TrimPrefix(model, p.prefix)
@@ -102,12 +258,36 @@ func (u *UnifiedClient) resolveClient(model string) (Client, string) {
 	return u.defaultClient(), model
 }
 
+// SupportsModel reports whether a configured provider serves model, either by
+// its prefix or, for unprefixed names, through the default provider.
+func (u *UnifiedClient) SupportsModel(model string) bool {
+	switch {
+	case strings.HasPrefix(model, "claude-"):
+		return u.anthropic != nil
+	case strings.HasPrefix(model, "gpt-"), strings.HasPrefix(model, "o1-"), strings.HasPrefix(model, "o3-"):
+		return u.openai != nil
+	case strings.HasPrefix(model, "ollama/"):
+		return u.ollama != nil
+	case strings.HasPrefix(model, "bedrock/"):
+		return u.bedrock != nil
+	case strings.HasPrefix(model, "command-"):
+		return u.cohere != nil
+	case strings.HasPrefix(model, "hf/"):
+		return u.huggingface != nil
+	case strings.HasPrefix(model, "openrouter/"):
+		return u.openrouter != nil
+	}
+	return u.openai != nil || u.anthropic != nil || u.ollama != nil
+}
+
 func (u *UnifiedClient) defaultClient() Client {
-	clients := []Client{u.openai, u.anthropic, u.ollama}
-	for _, c := range clients {
-		if c != nil {
-			return c
-		}
+	switch {
+	case u.openai != nil:
+		return u.openai
+	case u.anthropic != nil:
+		return u.anthropic
+	case u.ollama != nil:
+		return u.ollama
 	}
 	return nil
 }
@@ -142,7 +322,27 @@ func (u *UnifiedClient) EmbedBatch(ctx context.Context, model string, inputs []s
 	return client.EmbedBatch(ctx, resolvedModel, inputs)
 }
 
+// EmbedBatchWithOptions generates embeddings for multiple inputs, reporting progress.
+func (u *UnifiedClient) EmbedBatchWithOptions(ctx context.Context, model string, inputs []string, opts EmbedBatchOptions) ([]EmbeddingResponse, error) {
+	client, resolvedModel := u.resolveEmbeddingClient(model)
+	if client == nil {
+		return nil, fmt.Errorf("no embedding client available for model: %s", model)
+	}
+	if bc, ok := client.(BatchEmbeddingClient); ok {
+		return bc.EmbedBatchWithOptions(ctx, resolvedModel, inputs, opts)
+	}
+	return client.EmbedBatch(ctx, resolvedModel, inputs)
+}
+
 func (u *UnifiedClient) resolveEmbeddingClient(model string) (EmbeddingClient, string) {
+	client, resolvedModel := u.embeddingProvider(model)
+	if client == nil || u.embedCache == nil {
+		return client, resolvedModel
+	}
+	return NewCachingEmbeddingClient(client, u.embedCache), resolvedModel
+}
+
+func (u *UnifiedClient) embeddingProvider(model string) (EmbeddingClient, string) {
 	// Ollama embedding models
 	if strings.HasPrefix(model, "ollama/") {
 		if u.ollamaEmbed == nil {
@@ -151,6 +351,22 @@ func (u *UnifiedClient) resolveEmbeddingClient(model string) (EmbeddingClient, s
 		return u.ollamaEmbed, strings.TrimPrefix(model, "ollama/")
 	}
 
+	// Cohere embedding models (embed-multilingual-v3.0, embed-english-v3.0, etc.)
+	if strings.HasPrefix(model, "embed-") {
+		if u.cohere == nil {
+			return nil, model
+		}
+		return u.cohere, model
+	}
+
+	// HuggingFace feature-extraction models (hf/sentence-transformers/all-MiniLM-L6-v2, etc.)
+	if strings.HasPrefix(model, "hf/") {
+		if u.huggingface == nil {
+			return nil, model
+		}
+		return u.huggingface, strings.TrimPrefix(model, "hf/")
+	}
+
 	// OpenAI embedding models (text-embedding-3-small, text-embedding-3-large, etc.)
 	if strings.HasPrefix(model, "text-embedding-") {
 		if u.openai == nil {
@@ -1,6 +1,8 @@
 package monitor
 
 import (
+	"math"
+	"slices"
 	"sync"
 	"time"
 )
@@ -11,9 +13,14 @@ type MetricsCollector interface {
 }
 
 type InMemoryCollector struct {
+	// TimeSeries keeps every recorded NodeMetrics per node, not just the
+	// latest, for Percentile and ErrorRate across runs. Set it before recording.
+	TimeSeries bool
+
 	mu         sync.RWMutex
 	pipelineID string
 	metrics    map[string]NodeMetrics
+	history    map[string][]NodeMetrics
 	startTime  time.Time
 }
 
@@ -21,6 +28,7 @@ func NewInMemoryCollector(pipelineID string) *InMemoryCollector {
 	return &InMemoryCollector{
 		pipelineID: pipelineID,
 		metrics:    make(map[string]NodeMetrics),
+		history:    make(map[string][]NodeMetrics),
 		startTime:  time.Now(),
 	}
 }
@@ -29,6 +37,60 @@ func (c *InMemoryCollector) Record(metrics NodeMetrics) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.metrics[metrics.NodeID] = metrics
+	if c.TimeSeries {
+		c.history[metrics.NodeID] = append(c.history[metrics.NodeID], metrics)
+	}
+}
+
+// Percentile returns the pth percentile (0-100, nearest rank) of the node's
+// recorded durations. Without TimeSeries only the latest run is considered.
+func (c *InMemoryCollector) Percentile(nodeID string, p float64) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	samples := c.samples(nodeID)
+	if len(samples) == 0 {
+		return 0
+	}
+
+	durations := make([]time.Duration, len(samples))
+	for i, m := range samples {
+		durations[i] = m.Duration
+	}
+	slices.Sort(durations)
+
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	return durations[min(max(rank, 1), len(durations))-1]
+}
+
+// ErrorRate returns the fraction (0-1) of the node's recorded runs that failed.
+// Without TimeSeries only the latest run is considered.
+func (c *InMemoryCollector) ErrorRate(nodeID string) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	samples := c.samples(nodeID)
+	if len(samples) == 0 {
+		return 0
+	}
+
+	failed := 0
+	for _, m := range samples {
+		if !m.Success {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(samples))
+}
+
+// samples returns the node's history, or its latest metrics outside TimeSeries mode.
+// The caller must hold c.mu.
+func (c *InMemoryCollector) samples(nodeID string) []NodeMetrics {
+	if h := c.history[nodeID]; len(h) > 0 {
+		return h
+	}
+	if m, ok := c.metrics[nodeID]; ok {
+		return []NodeMetrics{m}
+	}
+	return nil
 }
 
 func (c *InMemoryCollector) Flush() PipelineMetrics {
@@ -37,12 +99,14 @@ func (c *InMemoryCollector) Flush() PipelineMetrics {
 
 	var totalTokens int
 	var totalDuration time.Duration
+	var totalCost float64
 
 	nodeMetrics := make(map[string]NodeMetrics, len(c.metrics))
 	for k, v := range c.metrics {
 		nodeMetrics[k] = v
 		totalTokens += v.TokensIn + v.TokensOut
 		totalDuration += v.Duration
+		totalCost += v.CostUSD
 	}
 
 	return PipelineMetrics{
@@ -52,6 +116,7 @@ func (c *InMemoryCollector) Flush() PipelineMetrics {
 		NodeMetrics:   nodeMetrics,
 		StartTime:     c.startTime,
 		EndTime:       time.Now(),
+		CostUSD:       totalCost,
 	}
 }
 
@@ -59,6 +124,7 @@ func (c *InMemoryCollector) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.metrics = make(map[string]NodeMetrics)
+	c.history = make(map[string][]NodeMetrics)
 	c.startTime = time.Now()
 }
 
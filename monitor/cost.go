@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// CostTracker is a MetricsCollector that accumulates spend by model and node.
+// When a price table is set, node costs are computed from token counts;
+// otherwise the CostUSD reported on each NodeMetrics is used.
+type CostTracker struct {
+	mu         sync.RWMutex
+	pipelineID string
+	prices     PriceTable
+	metrics    map[string]NodeMetrics
+	byModel    map[string]float64
+	byNode     map[string]float64
+	total      float64
+	startTime  time.Time
+}
+
+func NewCostTracker(pipelineID string, prices PriceTable) *CostTracker {
+	return &CostTracker{
+		pipelineID: pipelineID,
+		prices:     prices,
+		metrics:    make(map[string]NodeMetrics),
+		byModel:    make(map[string]float64),
+		byNode:     make(map[string]float64),
+		startTime:  time.Now(),
+	}
+}
+
+func (c *CostTracker) Record(metrics NodeMetrics) {
+	if c.prices != nil {
+		metrics.CostUSD = c.prices.Cost(metrics.Model, metrics.TokensIn, metrics.TokensOut)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics[metrics.NodeID] = metrics
+	c.byModel[metrics.Model] += metrics.CostUSD
+	c.byNode[metrics.NodeID] += metrics.CostUSD
+	c.total += metrics.CostUSD
+}
+
+func (c *CostTracker) Flush() PipelineMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var totalTokens int
+	var totalDuration time.Duration
+
+	nodeMetrics := make(map[string]NodeMetrics, len(c.metrics))
+	for k, v := range c.metrics {
+		nodeMetrics[k] = v
+		totalTokens += v.TokensIn + v.TokensOut
+		totalDuration += v.Duration
+	}
+
+	return PipelineMetrics{
+		PipelineID:    c.pipelineID,
+		TotalTokens:   totalTokens,
+		TotalDuration: totalDuration,
+		NodeMetrics:   nodeMetrics,
+		StartTime:     c.startTime,
+		EndTime:       time.Now(),
+		CostUSD:       c.total,
+	}
+}
+
+// TotalCostUSD returns the accumulated cost of all recorded calls.
+func (c *CostTracker) TotalCostUSD() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.total
+}
+
+// CostByModel returns a copy of the accumulated cost per model name.
+func (c *CostTracker) CostByModel() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return copyCosts(c.byModel)
+}
+
+// CostByNode returns a copy of the accumulated cost per node ID.
+func (c *CostTracker) CostByNode() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return copyCosts(c.byNode)
+}
+
+func (c *CostTracker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = make(map[string]NodeMetrics)
+	c.byModel = make(map[string]float64)
+	c.byNode = make(map[string]float64)
+	c.total = 0
+	c.startTime = time.Now()
+}
+
+func copyCosts(m map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -9,6 +9,8 @@ type NodeMetrics struct {
 	Duration  time.Duration `json:"duration"`
 	Success   bool          `json:"success"`
 	Error     string        `json:"error,omitempty"`
+	Model     string        `json:"model,omitempty"`
+	CostUSD   float64       `json:"cost_usd,omitempty"`
 }
 
 type PipelineMetrics struct {
@@ -18,6 +20,7 @@ type PipelineMetrics struct {
 	NodeMetrics  map[string]NodeMetrics `json:"node_metrics"`
 	StartTime    time.Time              `json:"start_time"`
 	EndTime      time.Time              `json:"end_time"`
+	CostUSD      float64                `json:"cost_usd"`
 }
 
 type ObserveConfig struct {
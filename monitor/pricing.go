@@ -0,0 +1,67 @@
+package monitor
+
+import "strings"
+
+// ModelPrice is the USD price per 1K tokens for a model.
+type ModelPrice struct {
+	InputPer1KTokens  float64 `json:"input_per_1k_tokens"`
+	OutputPer1KTokens float64 `json:"output_per_1k_tokens"`
+}
+
+// PriceTable maps model names (or name prefixes) to prices.
+type PriceTable map[string]ModelPrice
+
+// DefaultPriceTable returns approximate list prices for common OpenAI and
+// Anthropic models. Callers may copy and override entries.
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		"gpt-4o":                 {InputPer1KTokens: 0.0025, OutputPer1KTokens: 0.01},
+		"gpt-4o-mini":            {InputPer1KTokens: 0.00015, OutputPer1KTokens: 0.0006},
+		"gpt-4.1":                {InputPer1KTokens: 0.002, OutputPer1KTokens: 0.008},
+		"gpt-4.1-mini":           {InputPer1KTokens: 0.0004, OutputPer1KTokens: 0.0016},
+		"gpt-4.1-nano":           {InputPer1KTokens: 0.0001, OutputPer1KTokens: 0.0004},
+		"gpt-4-turbo":            {InputPer1KTokens: 0.01, OutputPer1KTokens: 0.03},
+		"gpt-4":                  {InputPer1KTokens: 0.03, OutputPer1KTokens: 0.06},
+		"gpt-3.5-turbo":          {InputPer1KTokens: 0.0005, OutputPer1KTokens: 0.0015},
+		"o1":                     {InputPer1KTokens: 0.015, OutputPer1KTokens: 0.06},
+		"o1-mini":                {InputPer1KTokens: 0.0011, OutputPer1KTokens: 0.0044},
+		"o3-mini":                {InputPer1KTokens: 0.0011, OutputPer1KTokens: 0.0044},
+		"claude-opus-4":          {InputPer1KTokens: 0.015, OutputPer1KTokens: 0.075},
+		"claude-sonnet-4":        {InputPer1KTokens: 0.003, OutputPer1KTokens: 0.015},
+		"claude-3-7-sonnet":      {InputPer1KTokens: 0.003, OutputPer1KTokens: 0.015},
+		"claude-3-5-sonnet":      {InputPer1KTokens: 0.003, OutputPer1KTokens: 0.015},
+		"claude-3-5-haiku":       {InputPer1KTokens: 0.0008, OutputPer1KTokens: 0.004},
+		"claude-3-opus":          {InputPer1KTokens: 0.015, OutputPer1KTokens: 0.075},
+		"claude-3-haiku":         {InputPer1KTokens: 0.00025, OutputPer1KTokens: 0.00125},
+		"text-embedding-3-small": {InputPer1KTokens: 0.00002},
+		"text-embedding-3-large": {InputPer1KTokens: 0.00013},
+	}
+}
+
+// Lookup returns the price for model, matching exactly first and then by the
+// longest table key that prefixes the model name (e.g. dated snapshots).
+func (t PriceTable) Lookup(model string) (ModelPrice, bool) {
+	if p, ok := t[model]; ok {
+		return p, true
+	}
+
+	var best string
+	for name := range t {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return t[best], true
+}
+
+// Cost estimates the USD cost of a call. Unknown models cost zero.
+func (t PriceTable) Cost(model string, tokensIn, tokensOut int) float64 {
+	p, ok := t.Lookup(model)
+	if !ok {
+		return 0
+	}
+	return float64(tokensIn)/1000*p.InputPer1KTokens + float64(tokensOut)/1000*p.OutputPer1KTokens
+}
@@ -0,0 +1,70 @@
+package pipelines
+
+import (
+	"fmt"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// NewMapReducePipeline creates a pipeline for documents longer than the context window.
+// The mapper applies mapPrompt to each chunk of the input via the map_chunks tool,
+// then the reducer combines the per-chunk results using reducePrompt.
+func NewMapReducePipeline(mapPrompt, reducePrompt string) *config.PipelineConfig {
+	mapperPrompt := "Process the user's document with the map_chunks tool. " +
+		"Pass the complete document as input and use this instruction as the prompt:\n\n" +
+		mapPrompt + "\n\n" +
+		"Return the tool's per-chunk results without summarizing them."
+
+	return config.NewPipeline("map-reduce", "Map-Reduce").
+		Node("mapper", config.NodeWorker).
+		Prompt(mapperPrompt).
+		Tools("map_chunks").
+		MaxIterations(2).
+		Done().
+		Node("reducer", config.NodeSynthesizer).
+		Prompt(reducePrompt).
+		Done().
+		Edge("mapper", "reducer").
+		Build()
+}
+
+// mapReduceBranches is the number of map nodes NewParallelMapReducePipeline
+// fans out to.
+const mapReduceBranches = 8
+
+// NewParallelMapReducePipeline creates a map-reduce pipeline that runs the map
+// step as concurrent pipeline nodes rather than through the map_chunks tool.
+// A splitter cuts the input into chunks of at most chunkSize characters and
+// sends one to each of up to 8 map workers (map_1 … map_8) applying mapPrompt;
+// inputs longer than 8 chunks are spread evenly across them. A combine
+// aggregator joins the map outputs with "---" delimiters and the reducer
+// merges them using reducePrompt.
+func NewParallelMapReducePipeline(mapPrompt, reducePrompt string, chunkSize int) *config.PipelineConfig {
+	mapNodes := make([]string, mapReduceBranches)
+	for i := range mapNodes {
+		mapNodes[i] = fmt.Sprintf("map_%d", i+1)
+	}
+
+	b := config.NewPipeline("parallel-map-reduce", "Parallel Map-Reduce").
+		Node("splitter", config.NodeSplitter).
+		ChunkSize(chunkSize).
+		TargetNodes(mapNodes...).
+		Done().
+		Node("combine", config.NodeAggregator).
+		Delimiter("\n\n---\n\n").
+		Done().
+		Node("reducer", config.NodeSynthesizer).
+		Prompt(reducePrompt).
+		Done()
+
+	for i, id := range mapNodes {
+		b = b.Node(id, config.NodeWorker).
+			Prompt(mapPrompt).
+			InputTemplate(fmt.Sprintf("{{index .Vars.chunks %d}}", i)).
+			Done().
+			FanOutEdge("splitter", id).
+			Edge(id, "combine")
+	}
+
+	return b.Edge("combine", "reducer").Build()
+}
@@ -0,0 +1,45 @@
+package pipelines
+
+import (
+	"fmt"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// NewMultiQueryRAGPipeline creates a RAG pipeline that searches with several
+// rephrasings of the user's question, so documents using different vocabulary
+// are still retrieved. numQueries defaults to 3.
+func NewMultiQueryRAGPipeline(systemPrompt string, numQueries int) *config.PipelineConfig {
+	if numQueries <= 0 {
+		numQueries = 3
+	}
+
+	expanderPrompt := fmt.Sprintf(
+		"Rewrite the user's question as %d alternative search queries. "+
+			"Vary the vocabulary and phrasing while keeping the meaning. "+
+			"Return the original question followed by the %d alternatives, one query per line, with no numbering or commentary.",
+		numQueries, numQueries,
+	)
+
+	retrieverPrompt := "You are given a list of search queries, one per line. " +
+		"Call the similarity_search tool once for each query. " +
+		"Combine the results, keeping each document only once by its ID, " +
+		"and return the unique documents with their IDs and content. " +
+		"Begin your answer with the first query, which is the user's original question."
+
+	return config.NewPipeline("multi-query-rag", "Multi-Query RAG").
+		Node("query_expander", config.NodeLLM).
+		Prompt(expanderPrompt).
+		Done().
+		Node("retriever", config.NodeWorker).
+		Prompt(retrieverPrompt).
+		Tools("similarity_search").
+		MaxIterations(numQueries+2).
+		Done().
+		Node("generator", config.NodeLLM).
+		Prompt(systemPrompt).
+		Done().
+		Edge("query_expander", "retriever").
+		Edge("retriever", "generator").
+		Build()
+}
@@ -0,0 +1,30 @@
+package pipelines
+
+import (
+	"strings"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// NewReActPipeline creates a single-worker ReAct agent that interleaves
+// reasoning with tool calls in a Thought/Action/Observation loop.
+// Each iteration's thought and action are recorded on the worker's spans.
+func NewReActPipeline(systemPrompt string, toolNames []string) *config.PipelineConfig {
+	reactPrompt := systemPrompt + "\n\n" +
+		"Solve the task step by step. Available tools: " + strings.Join(toolNames, ", ") + ".\n\n" +
+		"In each step, write:\n" +
+		"Thought: your reasoning about what to do next\n" +
+		"Action: the tool you will call and why\n" +
+		"Then call that tool. Its result is your Observation.\n\n" +
+		"Repeat until you can answer, then write:\n" +
+		"Thought: I now know the answer\n" +
+		"Final Answer: the answer to the task"
+
+	return config.NewPipeline("react", "ReAct Agent").
+		Node("agent", config.NodeWorker).
+		Prompt(reactPrompt).
+		Tools(toolNames...).
+		MaxIterations(10).
+		Done().
+		Build()
+}
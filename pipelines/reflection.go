@@ -0,0 +1,50 @@
+package pipelines
+
+import (
+	"fmt"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// NewReflectionPipeline creates a self-critique pipeline: a draft followed by
+// rounds of critique and revision. For rounds=2 the nodes are draft, critique_1,
+// refine_1, critique_2, refine_2. Each refine node receives the text under
+// review followed by its critique, and every critique and refine input is
+// prefixed with its round number. rounds defaults to 1.
+func NewReflectionPipeline(taskPrompt, critiquePrompt, refinePrompt string, rounds int) *config.PipelineConfig {
+	if rounds <= 0 {
+		rounds = 1
+	}
+
+	refineInstructions := refinePrompt + "\n\n" +
+		"You will receive the current draft followed by a critique of it. " +
+		"Return only the revised draft."
+
+	b := config.NewPipeline("reflection", "Reflection").
+		Node("draft", config.NodeLLM).
+		Prompt(taskPrompt).
+		Done()
+
+	prev := "draft"
+	for i := 1; i <= rounds; i++ {
+		critique := fmt.Sprintf("critique_%d", i)
+		refine := fmt.Sprintf("refine_%d", i)
+
+		round := fmt.Sprintf("Round %d of %d.\n\n{{.Content}}", i, rounds)
+
+		b = b.Node(critique, config.NodeEvaluator).
+			Prompt(critiquePrompt).
+			InputTemplate(round).
+			Done().
+			Node(refine, config.NodeLLM).
+			Prompt(refineInstructions).
+			InputTemplate(round).
+			Done().
+			Edge(prev, critique).
+			Edge(prev, refine).
+			Edge(critique, refine)
+		prev = refine
+	}
+
+	return b.Build()
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/server/store"
+)
+
+// AuthConfig configures bearer-token authentication. Authentication is
+// disabled when neither API keys nor a JWT secret are set.
+type AuthConfig struct {
+	APIKeys   []string // Optional: static bearer keys
+	AdminKeys []string // Optional: bearer keys granted superadmin access to /api/admin routes
+	JWTSecret string   // Optional: HS256 secret for JWT bearer tokens, which must carry a tenant_id claim
+}
+
+func (c AuthConfig) enabled() bool {
+	return len(c.APIKeys) > 0 || len(c.AdminKeys) > 0 || c.JWTSecret != ""
+}
+
+type adminKey struct{}
+
+func withAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// isAdmin reports whether the request authenticated as a superadmin, either
+// with an admin key or a JWT whose role claim is "superadmin".
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// authMiddleware rejects unauthenticated requests with 401 and stores the
+// JWT tenant_id claim in the request context for store scoping. A JWT request
+// whose X-Tenant-ID names another tenant gets 403.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	if !s.auth.enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if matchKey(token, s.auth.AdminKeys) {
+			next.ServeHTTP(w, r.WithContext(withAdmin(r.Context())))
+			return
+		}
+
+		if matchKey(token, s.auth.APIKeys) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.auth.JWTSecret != "" {
+			claims, err := verifyJWT(token, []byte(s.auth.JWTSecret))
+			tenantID, _ := claims["tenant_id"].(string)
+			if err == nil && tenantID == "" {
+				err = errors.New("missing tenant_id claim")
+			}
+			if header := r.Header.Get("X-Tenant-ID"); err == nil && header != "" && header != tenantID {
+				http.Error(w, "X-Tenant-ID does not match token tenant", http.StatusForbidden)
+				return
+			}
+			if err == nil {
+				ctx := store.WithTenant(r.Context(), tenantID)
+				if role, _ := claims["role"].(string); role == "superadmin" {
+					ctx = withAdmin(ctx)
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			s.logger.Debug("jwt rejected", slog.Any("error", err))
+		}
+
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func matchKey(token string, keys []string) bool {
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyJWT checks an HS256-signed JWT and its exp/nbf claims, returning the claims.
+func verifyJWT(token string, secret []byte) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("decode header: %w", err)
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported alg: %s", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, errors.New("invalid signature")
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	now := float64(time.Now().Unix())
+	if exp, ok := claims["exp"].(float64); ok && now >= exp {
+		return nil, errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return nil, errors.New("token not yet valid")
+	}
+	return claims, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
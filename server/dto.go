@@ -15,15 +15,37 @@ type ModelInfo struct {
 
 // Re-export types from store package
 type (
-	NodeInfo       = store.NodeInfo
-	EdgeInfo       = store.EdgeInfo
-	Position       = store.Position
-	PipelineInfo   = store.PipelineInfo
-	TraceInfo      = store.TraceInfo
-	SpanInfo       = store.SpanInfo
-	MetricsSummary = store.MetricsSummary
+	NodeInfo        = store.NodeInfo
+	EdgeInfo        = store.EdgeInfo
+	Position        = store.Position
+	PipelineInfo    = store.PipelineInfo
+	PipelineVersion = store.PipelineVersion
+	TraceInfo       = store.TraceInfo
+	SpanInfo        = store.SpanInfo
+	MetricsSummary  = store.MetricsSummary
+	TraceQuery      = store.TraceQuery
+	TracePage       = store.TracePage
+
+	RetentionPolicy = store.RetentionPolicy
+	PostgresConfig  = store.PostgresConfig
+
+	Session        = store.Session
+	SessionStore   = store.SessionStore
+	HistoryMessage = store.SessionMessage
 )
 
+type HealthResponse struct {
+	Status   string          `json:"status"`
+	Database *DatabaseHealth `json:"database,omitempty"`
+}
+
+// DatabaseHealth reports the result of pinging the trace store's database.
+type DatabaseHealth struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
 type InitResponse struct {
 	Models    []ModelInfo    `json:"models"`
 	Templates []PipelineInfo `json:"templates"`
@@ -34,6 +56,13 @@ type ToolInfo struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description"`
 	Parameters  json.RawMessage `json:"parameters"`
+	Enabled     bool            `json:"enabled"`
+}
+
+// UpdateToolRequest enables or disables a registered tool via PUT /api/tools.
+type UpdateToolRequest struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
 }
 
 type SavePipelineRequest struct {
@@ -51,11 +80,45 @@ type ChatRequest struct {
 	Pipeline     json.RawMessage  `json:"pipeline_config,omitempty"`
 	SystemPrompt string           `json:"system_prompt,omitempty"`
 	History      []HistoryMessage `json:"history,omitempty"`
+	SessionID    string           `json:"session_id,omitempty"` // Optional: persisted history; prepended before History
+	// Optional: node ID -> model name, overriding the pipeline's models for this request
+	ModelOverrides map[string]string `json:"model_overrides,omitempty"`
+}
+
+// CreateSessionResponse is returned by POST /api/sessions
+type CreateSessionResponse struct {
+	SessionID string `json:"session_id"`
 }
 
-type HistoryMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// SessionHistoryResponse is returned by GET /api/sessions/{id}/history
+type SessionHistoryResponse struct {
+	SessionID string           `json:"session_id"`
+	Messages  []HistoryMessage `json:"messages"`
+}
+
+// CreateTenantRequest is the body of POST /api/admin/tenants. An empty Tools
+// list seeds the tenant with every globally registered tool.
+type CreateTenantRequest struct {
+	ID    string   `json:"id"`
+	Tools []string `json:"tools,omitempty"`
+}
+
+// TenantInfo describes a tenant and the tools in its registry
+type TenantInfo struct {
+	ID    string   `json:"id"`
+	Tools []string `json:"tools"`
+}
+
+// IndexBatchRequest is the body of POST /api/vector/index-batch
+type IndexBatchRequest struct {
+	Documents []IndexDocument `json:"documents"`
+}
+
+// IndexDocument is one document to embed and upsert into the vector store
+type IndexDocument struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 type ChatResponse struct {
@@ -68,10 +131,13 @@ type Metadata struct {
 	OutputTokens int      `json:"output_tokens"`
 	ElapsedMs    int64    `json:"elapsed_ms"`
 	TokensPerSec *float64 `json:"tokens_per_sec,omitempty"`
+
+	EstimatedCostUSD float64 `json:"estimated_cost_usd,omitempty"`
 }
 
 type TraceListResponse struct {
-	Traces []TraceInfo `json:"traces"`
+	Traces    []TraceInfo `json:"traces"`
+	Truncated bool        `json:"truncated,omitempty"` // Traces holds only the store's newest ListLimit traces
 }
 
 type TraceDetailResponse struct {
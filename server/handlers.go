@@ -3,31 +3,57 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/hubenschmidt/go-fissio/config"
 	"github.com/hubenschmidt/go-fissio/core"
 	"github.com/hubenschmidt/go-fissio/engine"
 	"github.com/hubenschmidt/go-fissio/llm"
+	"github.com/hubenschmidt/go-fissio/server/store"
 	"github.com/hubenschmidt/go-fissio/tools"
 )
 
+// handleHealth reports "ok", pinging the database when the trace store
+// supports it and answering 503 if the ping fails.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("OK"))
+	resp := HealthResponse{Status: "ok"}
+	status := http.StatusOK
+
+	if checker, ok := s.traces.(store.HealthChecker); ok {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		err := checker.HealthCheck(ctx)
+		db := &DatabaseHealth{Status: "ok", LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+		if err != nil {
+			db.Status, db.Error = "error", err.Error()
+			resp.Status, status = "degraded", http.StatusServiceUnavailable
+		}
+		resp.Database = db
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
 }
 
 func (s *Server) handleInit(w http.ResponseWriter, r *http.Request) {
-	configs, err := s.pipelines.List(r.Context())
+	configs, err := s.pipelinesFor(store.TenantFromContext(r.Context())).List(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	resp := InitResponse{
 		Models:    s.models,
-		Templates: s.templates,
+		Templates: s.templateList(),
 		Configs:   configs,
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -35,19 +61,28 @@ func (s *Server) handleInit(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
-	names := s.registry.List()
+	registry := s.registryFor(r)
+	names := registry.List()
 	result := make([]ToolInfo, 0, len(names))
 
 	for _, name := range names {
-		t, ok := s.registry.Get(name)
+		t, ok := registry.Get(name)
 		if ok {
 			result = append(result, ToolInfo{
 				Name:        t.Name(),
 				Description: t.Description(),
 				Parameters:  t.Parameters(),
+				Enabled:     true,
 			})
 		}
 	}
+	for _, t := range s.disabledToolsFor(r) {
+		result = append(result, ToolInfo{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  t.Parameters(),
+		})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
@@ -60,6 +95,18 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	traceID := r.Header.Get("X-Trace-ID")
+	if traceID != "" && !validTraceID.MatchString(traceID) {
+		http.Error(w, "invalid X-Trace-ID: want 1-128 letters, digits, '.', '_', ':' or '-'", http.StatusBadRequest)
+		return
+	}
+	if traceID != "" {
+		if _, err := s.traces.Get(r.Context(), traceID); err == nil {
+			http.Error(w, "trace already exists: "+traceID, http.StatusConflict)
+			return
+		}
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -86,64 +133,93 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 
 	pipelineCfg := buildPipeline(rp)
 	resolver := engine.NewModelResolver(core.DefaultModelConfig("gpt-4"))
+	var memory *engine.ConversationMemory
+	if req.SessionID != "" {
+		mem, err := s.loadSession(r.Context(), req.SessionID)
+		if err != nil {
+			writeSSE(w, flusher, "stream", map[string]any{"content": "Error: load session: " + err.Error()})
+			writeSSE(w, flusher, "end", nil)
+			return
+		}
+		memory = mem
+	}
 	eng := engine.NewEngine(pipelineCfg, engine.EngineConfig{
-		Client:   s.client,
-		Registry: s.registry,
-		Resolver: resolver,
-	})
+		Client:         s.client,
+		Registry:       s.registryFor(r),
+		Resolver:       resolver,
+		Logger:         s.logger,
+		VectorStore:    s.vectorStore,
+		EmbedModel:     s.embedModel,
+		Memory:         memory,
+		ModelOverrides: req.ModelOverrides,
+	}).WithPricing(s.pricing)
 
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(r.Context(), 120*time.Second)
 	defer cancel()
+	if traceID != "" {
+		ctx = engine.WithTraceID(ctx, traceID)
+	}
 
-	result, err := eng.Run(ctx, req.Message)
+	result, streamedFinal, err := streamPipeline(ctx, w, flusher, eng, pipelineCfg, req.Message)
 	elapsed := time.Since(start)
 
+	pipelineName := rp.Name
+	if pipelineName == "" {
+		pipelineName = rp.ID
+	}
+
 	if err != nil {
 		writeSSE(w, flusher, "stream", map[string]any{"content": "Error: " + err.Error()})
 		writeSSE(w, flusher, "end", nil)
+		if result != nil {
+			s.recordTrace(r.Context(), TraceInfo{
+				TraceID:          result.TraceID,
+				PipelineID:       rp.ID,
+				PipelineName:     pipelineName,
+				Timestamp:        start.UnixMilli(),
+				Input:            req.Message,
+				Output:           "Error: " + err.Error(),
+				TotalElapsedMs:   elapsed.Milliseconds(),
+				EstimatedCostUSD: result.EstimatedCostUSD,
+				Status:           "error",
+				Spans:            toSpanInfos(result.TraceID, result.Spans),
+				Metadata:         tenantMetadata(r),
+			})
+		}
 		return
 	}
 
+	if memory != nil {
+		if err := s.saveSession(r.Context(), req.SessionID, memory); err != nil {
+			s.logger.Error("failed to save session", slog.String("session_id", req.SessionID), slog.Any("error", err))
+		}
+	}
+
 	var totalIn, totalOut int
 	for _, out := range result.Outputs {
 		totalIn += out.TokensIn
 		totalOut += out.TokensOut
 	}
 
-	writeSSE(w, flusher, "stream", map[string]any{"content": result.Content})
+	if !streamedFinal {
+		writeSSE(w, flusher, "stream", map[string]any{"content": result.Content})
+	}
 	writeSSE(w, flusher, "end", map[string]any{
 		"metadata": Metadata{
-			InputTokens:  totalIn,
-			OutputTokens: totalOut,
-			ElapsedMs:    elapsed.Milliseconds(),
+			InputTokens:      totalIn,
+			OutputTokens:     totalOut,
+			ElapsedMs:        elapsed.Milliseconds(),
+			EstimatedCostUSD: result.EstimatedCostUSD,
 		},
 	})
 
 	// Convert engine spans to server spans
-	traceID := fmt.Sprintf("trace_%d", time.Now().UnixNano())
-	spans := make([]SpanInfo, len(result.Spans))
-	for i, s := range result.Spans {
-		spans[i] = SpanInfo{
-			SpanID:       s.SpanID,
-			TraceID:      traceID,
-			NodeID:       s.NodeID,
-			NodeType:     s.NodeType,
-			StartTime:    s.StartTime,
-			EndTime:      s.EndTime,
-			Input:        s.Input,
-			Output:       s.Output,
-			InputTokens:  s.InputTokens,
-			OutputTokens: s.OutputTokens,
-		}
-	}
+	traceID = result.TraceID
+	spans := toSpanInfos(traceID, result.Spans)
 
 	// Record trace with spans
-	pipelineName := rp.Name
-	if pipelineName == "" {
-		pipelineName = rp.ID
-	}
-	if err := s.traces.Add(context.Background(), TraceInfo{
+	s.recordTrace(r.Context(), TraceInfo{
 		TraceID:           traceID,
 		PipelineID:        rp.ID,
 		PipelineName:      pipelineName,
@@ -154,11 +230,100 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		TotalInputTokens:  totalIn,
 		TotalOutputTokens: totalOut,
 		TotalToolCalls:    0,
+		EstimatedCostUSD:  result.EstimatedCostUSD,
 		Status:            "success",
 		Spans:             spans,
-	}); err != nil {
-		log.Printf("[trace] Failed to record trace: %v", err)
+		Metadata:          tenantMetadata(r),
+	})
+}
+
+// streamPipeline runs eng via RunStream, forwarding token chunks from terminal
+// nodes (those without outgoing edges) as SSE "stream" events. It reports
+// whether the final node's output has already been streamed to the client.
+func streamPipeline(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, eng *engine.Engine, cfg *config.PipelineConfig, input string) (*engine.EngineOutput, bool, error) {
+	events, err := eng.RunStream(ctx, input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	terminal := make(map[string]bool, len(cfg.Nodes))
+	for _, n := range cfg.Nodes {
+		terminal[n.ID] = true
+	}
+	for _, e := range cfg.Edges {
+		terminal[e.From.Node] = false
+	}
+
+	streamed := make(map[string]bool)
+	var result *engine.EngineOutput
+	for ev := range events {
+		switch ev.Type {
+		case engine.EventTokenChunk:
+			if terminal[ev.NodeID] {
+				streamed[ev.NodeID] = true
+				writeSSE(w, flusher, "stream", map[string]any{"content": ev.Content})
+			}
+		case engine.EventPipelineDone:
+			result = ev.Output
+		case engine.EventError:
+			return ev.Output, false, ev.Err
+		}
 	}
+
+	// The channel closes without a final event only when ctx is cancelled.
+	if result == nil {
+		return nil, false, ctx.Err()
+	}
+	return result, streamed[result.FinalNode], nil
+}
+
+// toSpanInfos flattens engine spans, including nested children, into trace spans.
+func toSpanInfos(traceID string, engineSpans []engine.Span) []SpanInfo {
+	spans := make([]SpanInfo, 0, len(engineSpans))
+	for _, s := range engineSpans {
+		spans = append(spans, SpanInfo{
+			SpanID:         s.SpanID,
+			TraceID:        traceID,
+			NodeID:         s.NodeID,
+			NodeType:       s.NodeType,
+			StartTime:      s.StartTime,
+			EndTime:        s.EndTime,
+			Input:          s.Input,
+			Output:         s.Output,
+			InputTokens:    s.InputTokens,
+			OutputTokens:   s.OutputTokens,
+			ToolCallCount:  s.ToolCallCount,
+			IterationCount: s.IterationCount,
+			Metadata:       s.Metadata,
+		})
+		spans = append(spans, toSpanInfos(traceID, s.Children)...)
+	}
+	return spans
+}
+
+// validTraceID matches the X-Trace-ID values accepted as trace storage keys.
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// toolTenant returns the tenant whose TenantTools grant scopes the shared
+// registry: the authenticated tenant, or the X-Tenant-ID header when the
+// request carries none. It never selects a tenant's stores.
+func toolTenant(r *http.Request) string {
+	if tenantID := store.TenantFromContext(r.Context()); tenantID != "" {
+		return tenantID
+	}
+	return r.Header.Get("X-Tenant-ID")
+}
+
+// tenantMetadata labels a trace with the tenant that owns it, or with the
+// X-Tenant-ID tool tenant when the request is not tied to one.
+func tenantMetadata(r *http.Request) map[string]any {
+	if tenantID := store.TenantFromContext(r.Context()); tenantID != "" {
+		return map[string]any{"tenant_id": tenantID}
+	}
+	if tenantID := r.Header.Get("X-Tenant-ID"); tenantID != "" {
+		return map[string]any{"tool_tenant": tenantID}
+	}
+	return nil
 }
 
 func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req ChatRequest, flusher http.Flusher) {
@@ -166,10 +331,7 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 	ctx, cancel := context.WithTimeout(r.Context(), 120*time.Second)
 	defer cancel()
 
-	log.Println("╔══════════════════════════════════════════════════════════════")
-	log.Println("║ DIRECT CHAT")
-	log.Printf("║ Input: %.50s...", req.Message)
-	log.Println("╠══════════════════════════════════════════════════════════════")
+	s.logger.Debug("direct_chat_start", slog.Int("input_chars", len(req.Message)))
 
 	// Use provided system prompt or default
 	systemPrompt := "You are a helpful assistant."
@@ -177,8 +339,23 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 		systemPrompt = req.SystemPrompt
 	}
 
-	// Build messages with history if provided
+	// Build messages with session and request history if provided
+	var memory *engine.ConversationMemory
+	if req.SessionID != "" {
+		mem, err := s.loadSession(r.Context(), req.SessionID)
+		if err != nil {
+			writeSSE(w, flusher, "stream", map[string]any{"content": "Error: load session: " + err.Error()})
+			writeSSE(w, flusher, "end", nil)
+			return
+		}
+		memory = mem
+	}
 	messages := make([]llm.Message, 0, len(req.History)+1)
+	if memory != nil {
+		for _, m := range memory.Messages() {
+			messages = append(messages, llm.Message{Role: string(m.Role), Content: m.Content})
+		}
+	}
 	for _, h := range req.History {
 		messages = append(messages, llm.Message{Role: h.Role, Content: h.Content})
 	}
@@ -196,7 +373,7 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 	if err != nil {
 		writeSSE(w, flusher, "stream", map[string]any{"content": "Error: " + err.Error()})
 		writeSSE(w, flusher, "end", nil)
-		if err := s.traces.Add(context.Background(), TraceInfo{
+		s.recordTrace(r.Context(), TraceInfo{
 			TraceID:        fmt.Sprintf("trace_%d", time.Now().UnixNano()),
 			PipelineID:     "direct",
 			PipelineName:   "Direct Chat",
@@ -205,9 +382,8 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 			Output:         "Error: " + err.Error(),
 			TotalElapsedMs: time.Since(start).Milliseconds(),
 			Status:         "error",
-		}); err != nil {
-			log.Printf("[trace] Failed to record trace: %v", err)
-		}
+			Metadata:       tenantMetadata(r),
+		})
 		return
 	}
 
@@ -215,20 +391,32 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 
 	elapsed := time.Since(start)
 
-	log.Printf("║     ✓ Completed in %v", elapsed)
-	log.Printf("║     ← Response: %d chars, %d/%d tokens", len(fullContent), usage.PromptTokens, usage.CompletionTokens)
-	log.Println("╚══════════════════════════════════════════════════════════════")
+	if memory != nil && ctx.Err() == nil {
+		if err := s.appendSession(ctx, req.SessionID, memory, req.Message, fullContent); err != nil {
+			s.logger.Error("failed to save session", slog.String("session_id", req.SessionID), slog.Any("error", err))
+		}
+	}
+
+	s.logger.Info("direct_chat_complete",
+		slog.Duration("duration", elapsed),
+		slog.Int("output_chars", len(fullContent)),
+		slog.Int("tokens_in", usage.PromptTokens),
+		slog.Int("tokens_out", usage.CompletionTokens),
+	)
+
+	cost := s.pricing.Cost("gpt-4", usage.PromptTokens, usage.CompletionTokens)
 
 	writeSSE(w, flusher, "end", map[string]any{
 		"metadata": Metadata{
-			InputTokens:  usage.PromptTokens,
-			OutputTokens: usage.CompletionTokens,
-			ElapsedMs:    elapsed.Milliseconds(),
+			InputTokens:      usage.PromptTokens,
+			OutputTokens:     usage.CompletionTokens,
+			ElapsedMs:        elapsed.Milliseconds(),
+			EstimatedCostUSD: cost,
 		},
 	})
 
 	// Record trace
-	if err := s.traces.Add(context.Background(), TraceInfo{
+	s.recordTrace(r.Context(), TraceInfo{
 		TraceID:           fmt.Sprintf("trace_%d", time.Now().UnixNano()),
 		PipelineID:        "direct",
 		PipelineName:      "Direct Chat",
@@ -239,9 +427,19 @@ func (s *Server) handleDirectChat(w http.ResponseWriter, r *http.Request, req Ch
 		TotalInputTokens:  usage.PromptTokens,
 		TotalOutputTokens: usage.CompletionTokens,
 		TotalToolCalls:    0,
+		EstimatedCostUSD:  cost,
 		Status:            "success",
-	}); err != nil {
-		log.Printf("[trace] Failed to record trace: %v", err)
+		Metadata:          tenantMetadata(r),
+	})
+}
+
+// recordTrace persists a trace and updates Prometheus metrics when enabled.
+func (s *Server) recordTrace(ctx context.Context, t TraceInfo) {
+	if err := s.traces.Add(context.WithoutCancel(ctx), t); err != nil {
+		s.logger.Error("failed to record trace", slog.String("trace_id", t.TraceID), slog.Any("error", err))
+	}
+	if s.prom != nil {
+		s.prom.observe(t)
 	}
 }
 
@@ -256,7 +454,7 @@ func writeSSE(w http.ResponseWriter, flusher http.Flusher, eventType string, dat
 }
 
 func (s *Server) handlePipelineList(w http.ResponseWriter, r *http.Request) {
-	pipelines, err := s.pipelines.List(r.Context())
+	pipelines, err := s.pipelinesFor(store.TenantFromContext(r.Context())).List(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -272,7 +470,7 @@ func (s *Server) handlePipelineSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.pipelines.Save(r.Context(), PipelineInfo{
+	err := s.pipelinesFor(store.TenantFromContext(r.Context())).Save(r.Context(), PipelineInfo{
 		ID:          req.ID,
 		Name:        req.Name,
 		Description: req.Description,
@@ -280,6 +478,10 @@ func (s *Server) handlePipelineSave(w http.ResponseWriter, r *http.Request) {
 		Edges:       req.Edges,
 		Layout:      req.Layout,
 	})
+	if errors.Is(err, store.ErrConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -296,7 +498,7 @@ func (s *Server) handlePipelineDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.pipelines.Delete(r.Context(), req.ID); err != nil {
+	if err := s.pipelinesFor(store.TenantFromContext(r.Context())).Delete(r.Context(), req.ID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -304,14 +506,250 @@ func (s *Server) handlePipelineDelete(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
+func (s *Server) handlePipelineMermaid(w http.ResponseWriter, r *http.Request) {
+	p, err := s.findPipeline(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, buildPipeline(runtimeFromInfo(p)).Mermaid())
+}
+
+// handlePipelineExplain describes a saved pipeline's execution plan as plain text.
+func (s *Server) handlePipelineExplain(w http.ResponseWriter, r *http.Request) {
+	p, err := s.findPipeline(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	cfg := buildPipeline(runtimeFromInfo(p))
+	cfg.ID, cfg.Name = p.ID, p.Name
+	eng := engine.NewEngine(cfg, engine.EngineConfig{
+		Client:   s.client,
+		Registry: s.registryFor(r),
+		Resolver: engine.NewModelResolver(core.DefaultModelConfig("gpt-4")),
+		Logger:   s.logger,
+	})
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, eng.Explain())
+}
+
+// handlePipelineVersions lists a saved pipeline's versions, oldest first.
+func (s *Server) handlePipelineVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := s.pipelinesFor(store.TenantFromContext(r.Context())).ListVersions(r.Context(), r.PathValue("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+// handlePipelineVersionGet returns one saved version of a pipeline, e.g. to
+// roll back by saving it again.
+func (s *Server) handlePipelineVersionGet(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+	p, err := s.pipelinesFor(store.TenantFromContext(r.Context())).GetVersion(r.Context(), r.PathValue("id"), version)
+	if errors.Is(err, store.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// handlePipelineExport downloads a pipeline as a gzipped archive. Prompts are
+// included unless ?prompts=false.
+func (s *Server) handlePipelineExport(w http.ResponseWriter, r *http.Request) {
+	p, err := s.findPipeline(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	cfg := buildPipeline(runtimeFromInfo(p))
+	cfg.ID, cfg.Name, cfg.Description = p.ID, p.Name, p.Description
+
+	data, err := config.ExportPipeline(cfg, r.URL.Query().Get("prompts") != "false")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", p.ID+".fissio.json.gz"))
+	w.Write(data)
+}
+
+// handlePipelineImport saves a pipeline uploaded as the "file" field of a
+// multipart form, in the archive format written by handlePipelineExport.
+func (s *Server) handlePipelineImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 10<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cfg, err := config.ImportPipeline(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkSavable(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.pipelinesFor(store.TenantFromContext(r.Context())).Save(r.Context(), infoFromConfig(cfg))
+	if errors.Is(err, store.ErrConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"success": true, "id": cfg.ID})
+}
+
+// findPipeline looks up a saved pipeline, falling back to the built-in templates.
+func (s *Server) findPipeline(ctx context.Context, id string) (PipelineInfo, error) {
+	p, err := s.pipelinesFor(store.TenantFromContext(ctx)).Get(ctx, id)
+	if !errors.Is(err, store.ErrNotFound) {
+		return p, err
+	}
+	for _, t := range s.templateList() {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return PipelineInfo{}, err
+}
+
+func (s *Server) handlePipelineDryRun(w http.ResponseWriter, r *http.Request) {
+	var rp runtimePipeline
+	if err := json.NewDecoder(r.Body).Decode(&rp); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	eng := engine.NewEngine(buildPipeline(rp), engine.EngineConfig{
+		Client:   s.client,
+		Registry: s.registryFor(r),
+		Resolver: engine.NewModelResolver(core.DefaultModelConfig("gpt-4")),
+		Logger:   s.logger,
+	})
+
+	report, err := eng.DryRun(r.Context(), "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
 func (s *Server) handleTraceList(w http.ResponseWriter, r *http.Request) {
 	traces, err := s.traces.List(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	resp := TraceListResponse{Traces: traces}
+	if l, ok := s.traces.(store.ListLimiter); ok {
+		resp.Truncated = len(traces) >= l.ListLimit()
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(TraceListResponse{Traces: traces})
+	json.NewEncoder(w).Encode(resp)
+}
+
+var exportContentTypes = map[string]string{
+	store.ExportJSON:   "application/json",
+	store.ExportNDJSON: "application/x-ndjson",
+	store.ExportCSV:    "text/csv",
+}
+
+func (s *Server) handleTraceExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = store.ExportJSON
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		http.Error(w, "unsupported format: "+format, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="traces.%s"`, format))
+	if err := s.traces.Export(r.Context(), w, format); err != nil {
+		s.logger.Error("trace export failed", slog.String("format", format), slog.Any("error", err))
+	}
+}
+
+func (s *Server) handlePipelineTraces(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := s.traces.Query(r.Context(), TraceQuery{
+		PipelineID: r.PathValue("id"),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if page.Traces == nil {
+		page.Traces = []TraceInfo{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// queryInt parses a non-negative integer query parameter, returning def when absent.
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return n, nil
 }
 
 func (s *Server) handleTraceGet(w http.ResponseWriter, r *http.Request) {
@@ -335,6 +773,14 @@ func (s *Server) handleTraceDelete(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
+func (s *Server) handlePrometheus(w http.ResponseWriter, r *http.Request) {
+	if s.prom == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.prom.handler().ServeHTTP(w, r)
+}
+
 func (s *Server) handleMetricsSummary(w http.ResponseWriter, r *http.Request) {
 	summary, err := s.traces.Summary(r.Context())
 	if err != nil {
@@ -353,16 +799,155 @@ type runtimePipeline struct {
 }
 
 type runtimeNode struct {
-	ID     string   `json:"id"`
-	Type   string   `json:"type"`
-	Model  *string  `json:"model,omitempty"`
-	Prompt *string  `json:"prompt,omitempty"`
-	Tools  []string `json:"tools,omitempty"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	Model        *string  `json:"model,omitempty"`
+	Prompt       *string  `json:"prompt,omitempty"`
+	SystemPrompt *string  `json:"system_prompt,omitempty"`
+	Tools        []string `json:"tools,omitempty"`
+	TargetNodes  []string `json:"target_nodes,omitempty"`
+	TimeoutSecs  int      `json:"timeout_secs,omitempty"`
+
+	ConditionNode   string `json:"condition_node,omitempty"`
+	MaxIter         int    `json:"max_iter,omitempty"`
+	ToolConcurrency int    `json:"tool_concurrency,omitempty"`
+
+	ResponseSchema json.RawMessage `json:"response_schema,omitempty"`
+	InputTemplate  string          `json:"input_template,omitempty"`
+
+	MaxInputTokens     int    `json:"max_input_tokens,omitempty"`
+	TruncationStrategy string `json:"truncation_strategy,omitempty"`
+
+	OutputParser    *config.OutputParser `json:"output_parser,omitempty"`
+	OutputTransform string               `json:"output_transform,omitempty"`
+	JoinStrategy    string               `json:"join_strategy,omitempty"`
+
+	ChunkSize int    `json:"chunk_size,omitempty"`
+	Delimiter string `json:"delimiter,omitempty"`
 }
 
 type runtimeEdge struct {
-	From json.RawMessage `json:"from"`
-	To   json.RawMessage `json:"to"`
+	From      json.RawMessage `json:"from"`
+	To        json.RawMessage `json:"to"`
+	EdgeType  string          `json:"edge_type,omitempty"`
+	Condition string          `json:"condition,omitempty"`
+}
+
+// runtimeFromInfo converts a saved pipeline into its runtime form.
+func runtimeFromInfo(p PipelineInfo) runtimePipeline {
+	rp := runtimePipeline{ID: p.ID, Name: p.Name}
+	for _, n := range p.Nodes {
+		node := runtimeNode{
+			ID:                 n.ID,
+			Type:               n.NodeType,
+			Model:              n.Model,
+			Prompt:             n.Prompt,
+			SystemPrompt:       n.SystemPrompt,
+			Tools:              n.Tools,
+			TargetNodes:        n.TargetNodes,
+			TimeoutSecs:        n.TimeoutSecs,
+			ConditionNode:      n.ConditionNode,
+			MaxIter:            n.MaxIter,
+			ToolConcurrency:    n.ToolConcurrency,
+			ResponseSchema:     n.ResponseSchema,
+			InputTemplate:      n.InputTemplate,
+			MaxInputTokens:     n.MaxInputTokens,
+			TruncationStrategy: n.TruncationStrategy,
+			OutputTransform:    n.OutputTransform,
+			JoinStrategy:       n.JoinStrategy,
+			ChunkSize:          n.ChunkSize,
+			Delimiter:          n.Delimiter,
+		}
+		if len(n.OutputParser) > 0 {
+			node.OutputParser = &config.OutputParser{}
+			json.Unmarshal(n.OutputParser, node.OutputParser)
+		}
+		rp.Nodes = append(rp.Nodes, node)
+	}
+	for _, e := range p.Edges {
+		edge := runtimeEdge{From: e.From, To: e.To, Condition: e.Condition}
+		if e.EdgeType != nil {
+			edge.EdgeType = *e.EdgeType
+		}
+		rp.Edges = append(rp.Edges, edge)
+	}
+	return rp
+}
+
+// checkSavable reports settings of cfg that PipelineInfo has no place for,
+// so callers can reject them instead of letting infoFromConfig drop them.
+// Saved nodes run with core.DefaultModelConfig, so other model parameters
+// would be lost too.
+func checkSavable(cfg *config.PipelineConfig) error {
+	if cfg.EntryNode != "" || len(cfg.Metadata) > 0 {
+		return fmt.Errorf("pipeline %s: entry_node and metadata cannot be saved", cfg.ID)
+	}
+	for _, n := range cfg.Nodes {
+		var field string
+		switch {
+		case len(n.NextNodes) > 0:
+			field = "next_nodes"
+		case len(n.Metadata) > 0:
+			field = "metadata"
+		case n.SubPipeline != nil:
+			field = "sub_pipeline"
+		case n.Model != core.ModelConfig{Name: n.Model.Name} && n.Model != core.DefaultModelConfig(n.Model.Name):
+			field = "model settings other than name"
+		}
+		if field != "" {
+			return fmt.Errorf("pipeline %s: node %s: %s cannot be saved", cfg.ID, n.ID, field)
+		}
+	}
+	return nil
+}
+
+// infoFromConfig converts a pipeline config into its saved form; see checkSavable.
+func infoFromConfig(cfg *config.PipelineConfig) PipelineInfo {
+	p := PipelineInfo{ID: cfg.ID, Name: cfg.Name, Description: cfg.Description}
+	for _, n := range cfg.Nodes {
+		node := NodeInfo{
+			ID:                 n.ID,
+			NodeType:           n.Type.String(),
+			Tools:              n.Tools,
+			TargetNodes:        n.TargetNodes,
+			TimeoutSecs:        int(n.Timeout() / time.Second),
+			ConditionNode:      n.ConditionNode,
+			MaxIter:            n.MaxIter,
+			ToolConcurrency:    n.ToolConcurrency,
+			ResponseSchema:     n.ResponseSchema,
+			InputTemplate:      n.InputTemplate,
+			MaxInputTokens:     n.MaxInputTokens,
+			TruncationStrategy: n.TruncationStrategy,
+			OutputTransform:    n.OutputTransform,
+			JoinStrategy:       n.JoinStrategy,
+			ChunkSize:          n.ChunkSize,
+			Delimiter:          n.Delimiter,
+		}
+		if n.Model.Name != "" {
+			node.Model = &n.Model.Name
+		}
+		if n.Prompt != "" {
+			node.Prompt = &n.Prompt
+		}
+		if n.SystemPrompt != "" {
+			node.SystemPrompt = &n.SystemPrompt
+		}
+		if n.OutputParser != nil {
+			node.OutputParser, _ = json.Marshal(n.OutputParser)
+		}
+		p.Nodes = append(p.Nodes, node)
+	}
+	for _, e := range cfg.Edges {
+		from, _ := json.Marshal(e.From.Node)
+		to, _ := json.Marshal(e.To.Node)
+		edge := EdgeInfo{From: from, To: to, Condition: e.Condition}
+		if e.Type != config.EdgeDefault {
+			edgeType := e.Type.String()
+			edge.EdgeType = &edgeType
+		}
+		p.Edges = append(p.Edges, edge)
+	}
+	return p
 }
 
 func buildPipeline(rp runtimePipeline) *config.PipelineConfig {
@@ -374,10 +959,29 @@ func buildPipeline(rp runtimePipeline) *config.PipelineConfig {
 		if n.Prompt != nil {
 			node.Prompt = *n.Prompt
 		}
+		if n.SystemPrompt != nil {
+			node.SystemPrompt = *n.SystemPrompt
+		}
 		if n.Model != nil {
 			node.Model = core.DefaultModelConfig(*n.Model)
 		}
 		node.Tools = n.Tools
+		node.TargetNodes = n.TargetNodes
+		node.TimeoutSecs = n.TimeoutSecs
+		node.ConditionNode = n.ConditionNode
+		if n.MaxIter > 0 {
+			node.MaxIter = n.MaxIter
+		}
+		node.ToolConcurrency = n.ToolConcurrency
+		node.ResponseSchema = n.ResponseSchema
+		node.InputTemplate = n.InputTemplate
+		node.MaxInputTokens = n.MaxInputTokens
+		node.TruncationStrategy = n.TruncationStrategy
+		node.OutputParser = n.OutputParser
+		node.OutputTransform = n.OutputTransform
+		node.JoinStrategy = n.JoinStrategy
+		node.ChunkSize = n.ChunkSize
+		node.Delimiter = n.Delimiter
 		cfg.AddNode(node)
 	}
 
@@ -394,6 +998,14 @@ func buildPipeline(rp runtimePipeline) *config.PipelineConfig {
 			to = toObj["node"]
 		}
 
+		if e.EdgeType == "conditional" {
+			cfg.AddConditionalEdge(from, to, e.Condition)
+			continue
+		}
+		if e.EdgeType == "fan_out" {
+			cfg.AddFanOutEdge(from, to)
+			continue
+		}
 		cfg.AddEdge(from, to)
 	}
 
@@ -403,8 +1015,8 @@ func buildPipeline(rp runtimePipeline) *config.PipelineConfig {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Tenant-ID, X-Trace-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// promMetrics holds the Prometheus collectors exposed on GET /metrics.
+type promMetrics struct {
+	registry  *prometheus.Registry
+	traces    prometheus.Counter
+	tokensIn  prometheus.Counter
+	tokensOut prometheus.Counter
+	latency   prometheus.Histogram
+	toolCalls prometheus.Counter
+}
+
+func newPromMetrics() *promMetrics {
+	m := &promMetrics{
+		registry: prometheus.NewRegistry(),
+		traces: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fissio_traces_total",
+			Help: "Total number of recorded traces.",
+		}),
+		tokensIn: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fissio_tokens_in_total",
+			Help: "Total number of input tokens consumed.",
+		}),
+		tokensOut: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fissio_tokens_out_total",
+			Help: "Total number of output tokens generated.",
+		}),
+		latency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fissio_latency_ms_histogram",
+			Help:    "End-to-end trace latency in milliseconds.",
+			Buckets: []float64{100, 500, 1000, 5000, 30000},
+		}),
+		toolCalls: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fissio_tool_calls_total",
+			Help: "Total number of tool calls executed.",
+		}),
+	}
+
+	m.registry.MustRegister(m.traces, m.tokensIn, m.tokensOut, m.latency, m.toolCalls)
+	return m
+}
+
+func (m *promMetrics) observe(t TraceInfo) {
+	m.traces.Inc()
+	m.tokensIn.Add(float64(t.TotalInputTokens))
+	m.tokensOut.Add(float64(t.TotalOutputTokens))
+	m.latency.Observe(float64(t.TotalElapsedMs))
+	m.toolCalls.Add(float64(t.TotalToolCalls))
+}
+
+func (m *promMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
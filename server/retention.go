@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// StartPruner deletes traces outside policy every interval until the server
+// is closed. The first pass runs immediately.
+func (s *Server) StartPruner(interval time.Duration, policy RetentionPolicy) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.pruneTraces(policy)
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+func (s *Server) pruneTraces(policy RetentionPolicy) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	n, err := s.traces.Prune(ctx, policy)
+	if err != nil {
+		s.logger.Error("trace pruning failed", slog.Any("error", err))
+		return
+	}
+	s.logger.Info("pruned traces", slog.Int("removed", n))
+}
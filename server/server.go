@@ -1,12 +1,18 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hubenschmidt/go-fissio/config"
 	"github.com/hubenschmidt/go-fissio/llm"
+	"github.com/hubenschmidt/go-fissio/monitor"
+	"github.com/hubenschmidt/go-fissio/pipelines"
 	"github.com/hubenschmidt/go-fissio/server/store"
 	"github.com/hubenschmidt/go-fissio/tools"
 	"github.com/hubenschmidt/go-fissio/vector"
@@ -18,12 +24,35 @@ type Config struct {
 	Registry    *tools.Registry
 	Models      []ModelInfo
 	Templates   []PipelineInfo
+	PipelineDir string // Optional: *.json/*.yaml pipelines added to Templates at startup and on Reload
 	OllamaURL   string // Optional: URL for Ollama model discovery
 	DatabaseDSN string // Optional: database connection string (postgres:// or sqlite path)
 
+	PostgresConfig PostgresConfig // Optional: connection pool settings for a postgres:// DatabaseDSN
+
+	TraceStore store.TraceStore // Optional: replaces the trace store at DatabaseDSN, e.g. store.NewRedisTraceStore
+
 	// Vector store configuration
 	VectorStore vector.Store // Optional: inject custom vector store
 	EmbedModel  string       // Embedding model (default: text-embedding-3-small)
+
+	EnablePrometheus bool // Expose Prometheus metrics on GET /metrics
+
+	Pricing monitor.PriceTable // Optional: defaults to monitor.DefaultPriceTable()
+
+	Auth AuthConfig // Optional: bearer-token authentication (disabled when empty)
+
+	TenantTools map[string][]string // Optional: tenant ID -> allowed tool names, keyed by the JWT tenant or else X-Tenant-ID (empty = single-tenant)
+
+	// Optional: dedicated pipeline store for tenants created via POST /api/admin/tenants
+	// (default: the shared store pinned to the tenant ID)
+	TenantPipelineStore func(tenantID string) (store.PipelineStore, error)
+
+	SessionMaxMessages int           // Optional: messages kept per chat session (default: 50)
+	SessionTTL         time.Duration // Optional: idle chat sessions expire after this (0 = never)
+	SessionStore       SessionStore  // Optional: defaults to SQLite at DatabaseDSN, or in-memory for Postgres
+
+	Logger *slog.Logger // Optional: defaults to slog.Default()
 }
 
 // Server is an HTTP server for the fissio agent framework.
@@ -31,14 +60,41 @@ type Server struct {
 	client      llm.Client
 	registry    *tools.Registry
 	models      []ModelInfo
-	templates   []PipelineInfo
 	pipelines   store.PipelineStore
 	traces      store.TraceStore
 	vectorStore vector.Store
+	embedModel  string
+	prom        *promMetrics
+	pricing     monitor.PriceTable
+	auth        AuthConfig
+	tenantTools map[string][]string
+	sessions    SessionStore
+	logger      *slog.Logger
+
+	sessionMaxMessages int
+
+	done      chan struct{} // closed by Close to stop background work
+	closeOnce sync.Once
+
+	templatesMu   sync.RWMutex
+	templates     []PipelineInfo // replaced wholesale by Reload
+	baseTemplates []PipelineInfo // Config.Templates or the defaults
+	pipelineDir   string
+
+	tenantsMu          sync.RWMutex
+	tenantRegistries   map[string]*tools.Registry
+	tenantPipelines    map[string]store.PipelineStore
+	disabledTools      map[string]disabledTool
+	newTenantPipelines func(tenantID string) (store.PipelineStore, error)
 }
 
 // New creates a new Server with the given configuration.
 func New(cfg Config) (*Server, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	registry := cfg.Registry
 	if registry == nil {
 		registry = tools.DefaultRegistry
@@ -53,11 +109,11 @@ func New(cfg Config) (*Server, error) {
 	if cfg.OllamaURL != "" {
 		ollamaModels, err := llm.DiscoverOllamaModels(cfg.OllamaURL)
 		if err != nil {
-			log.Printf("[ollama] Discovery failed (is Ollama running?): %v", err)
+			logger.Warn("ollama discovery failed (is Ollama running?)", slog.Any("error", err))
 		} else {
-			log.Printf("[ollama] Found %d local models", len(ollamaModels))
+			logger.Info("ollama models discovered", slog.Int("count", len(ollamaModels)))
 			for _, m := range ollamaModels {
-				log.Printf("[ollama]   - %s (%s)", m.Name, m.ID)
+				logger.Debug("ollama model", slog.String("name", m.Name), slog.String("id", m.ID))
 				models = append(models, ModelInfo{
 					ID:      m.ID,
 					Name:    m.Name,
@@ -74,43 +130,84 @@ func New(cfg Config) (*Server, error) {
 	}
 
 	// Initialize database stores
-	traceStore, pipelineStore, err := store.NewStores(cfg.DatabaseDSN)
+	traceStore, pipelineStore, err := store.NewStores(cfg.DatabaseDSN, cfg.PostgresConfig)
 	if err != nil {
 		return nil, fmt.Errorf("initialize stores: %w", err)
 	}
+	// The database stays open for the pipeline store, which closes it.
+	if cfg.TraceStore != nil {
+		traceStore = cfg.TraceStore
+	}
+
+	logger.Info("database storage initialized")
+
+	sessions := cfg.SessionStore
+	if sessions == nil {
+		sessions, err = newSessionStore(cfg.DatabaseDSN, cfg.SessionTTL)
+		if err != nil {
+			traceStore.Close()
+			pipelineStore.Close()
+			return nil, fmt.Errorf("initialize session store: %w", err)
+		}
+	}
 
-	log.Printf("[store] Initialized database storage")
+	sessionMaxMessages := cfg.SessionMaxMessages
+	if sessionMaxMessages <= 0 {
+		sessionMaxMessages = 50
+	}
 
 	// Initialize vector store
 	var vectorStore vector.Store
 	if cfg.VectorStore != nil {
 		vectorStore = cfg.VectorStore
 	} else if strings.HasPrefix(cfg.DatabaseDSN, "postgres://") || strings.HasPrefix(cfg.DatabaseDSN, "postgresql://") {
-		vs, err := vector.NewPgVectorStore(cfg.DatabaseDSN, 1536)
+		vs, err := vector.NewPgVectorStore(vector.PgVectorConfig{DSN: cfg.DatabaseDSN, Dimension: 1536})
 		if err != nil {
-			log.Printf("[vector] Failed to initialize pgvector: %v", err)
+			logger.Warn("pgvector initialization failed", slog.Any("error", err))
 		} else {
 			vectorStore = vs
-			log.Printf("[vector] Initialized pgvector store")
+			logger.Info("pgvector store initialized")
 		}
 	}
 	if vectorStore == nil {
 		vectorStore = vector.NewMemoryStore()
-		log.Printf("[vector] Using in-memory vector store")
+		logger.Info("using in-memory vector store")
+	}
+
+	embedModel := cfg.EmbedModel
+	if embedModel == "" {
+		embedModel = "text-embedding-3-small"
 	}
 
 	// Register semantic search tools if we have an embedding client
 	if embedder, ok := cfg.Client.(llm.EmbeddingClient); ok {
-		embedModel := cfg.EmbedModel
-		if embedModel == "" {
-			embedModel = "text-embedding-3-small"
+		search := tools.NewSimilaritySearchTool(vectorStore, embedder, embedModel)
+		if c, ok := cfg.Client.(interface{ CohereKey() string }); ok && c.CohereKey() != "" {
+			search.WithReranker(vector.NewCohereReranker(c.CohereKey(), ""))
 		}
-		registry.Register(tools.NewSimilaritySearchTool(vectorStore, embedder, embedModel))
+		registry.Register(search)
 		registry.Register(tools.NewIndexDocumentTool(vectorStore, embedder, embedModel))
-		log.Printf("[vector] Registered similarity_search and index_document tools (model: %s)", embedModel)
+		logger.Info("registered vector tools",
+			slog.Any("tools", []string{"similarity_search", "index_document"}),
+			slog.String("embed_model", embedModel),
+		)
+	}
+
+	if cfg.Client != nil && len(models) > 0 {
+		registry.Register(tools.NewMapChunksTool(cfg.Client, models[0].Model))
+	}
+
+	pricing := cfg.Pricing
+	if pricing == nil {
+		pricing = monitor.DefaultPriceTable()
 	}
 
-	return &Server{
+	var prom *promMetrics
+	if cfg.EnablePrometheus {
+		prom = newPromMetrics()
+	}
+
+	s := &Server{
 		client:      cfg.Client,
 		registry:    registry,
 		models:      models,
@@ -118,11 +215,44 @@ func New(cfg Config) (*Server, error) {
 		pipelines:   pipelineStore,
 		traces:      traceStore,
 		vectorStore: vectorStore,
-	}, nil
+		embedModel:  embedModel,
+		prom:        prom,
+		pricing:     pricing,
+		auth:        cfg.Auth,
+		tenantTools: cfg.TenantTools,
+		sessions:    sessions,
+		logger:      logger,
+		done:        make(chan struct{}),
+
+		sessionMaxMessages: sessionMaxMessages,
+
+		tenantRegistries:   make(map[string]*tools.Registry),
+		tenantPipelines:    make(map[string]store.PipelineStore),
+		disabledTools:      make(map[string]disabledTool),
+		newTenantPipelines: cfg.TenantPipelineStore,
+
+		baseTemplates: templates,
+		pipelineDir:   cfg.PipelineDir,
+	}
+
+	if err := s.Reload(); err != nil {
+		logger.Error("pipeline template load failed", slog.String("dir", cfg.PipelineDir), slog.Any("error", err))
+	}
+
+	return s, nil
 }
 
-// Close closes the server and releases resources.
+// Close closes the server and releases resources. Calls after the first
+// do nothing and return nil.
 func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() { err = s.close() })
+	return err
+}
+
+func (s *Server) close() error {
+	close(s.done)
+
 	var errs []error
 	if err := s.traces.Close(); err != nil {
 		errs = append(errs, err)
@@ -130,6 +260,16 @@ func (s *Server) Close() error {
 	if err := s.pipelines.Close(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := s.sessions.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	s.tenantsMu.Lock()
+	for _, ps := range s.tenantPipelines {
+		if err := ps.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.tenantsMu.Unlock()
 	if s.vectorStore != nil {
 		if err := s.vectorStore.Close(); err != nil {
 			errs = append(errs, err)
@@ -147,20 +287,46 @@ func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /metrics", s.handlePrometheus)
 	mux.HandleFunc("GET /init", s.handleInit)
 	mux.HandleFunc("GET /tools", s.handleTools)
+	mux.HandleFunc("PUT /api/tools", s.handleToolReplace)
+	mux.HandleFunc("DELETE /api/tools/{name}", s.handleToolDelete)
 	mux.HandleFunc("POST /chat", s.handleChat)
 
 	mux.HandleFunc("GET /pipelines", s.handlePipelineList)
 	mux.HandleFunc("POST /pipelines/save", s.handlePipelineSave)
 	mux.HandleFunc("POST /pipelines/delete", s.handlePipelineDelete)
 
+	mux.HandleFunc("POST /api/pipelines/dry-run", s.handlePipelineDryRun)
+	mux.HandleFunc("GET /api/pipelines/{id}/traces", s.handlePipelineTraces)
+	mux.HandleFunc("GET /api/pipelines/{id}/mermaid", s.handlePipelineMermaid)
+	mux.HandleFunc("GET /api/pipelines/{id}/explain", s.handlePipelineExplain)
+	mux.HandleFunc("GET /api/pipelines/{id}/versions", s.handlePipelineVersions)
+	mux.HandleFunc("GET /api/pipelines/{id}/versions/{version}", s.handlePipelineVersionGet)
+	mux.HandleFunc("GET /api/pipelines/{id}/export", s.handlePipelineExport)
+	mux.HandleFunc("POST /api/pipelines/import", s.handlePipelineImport)
 	mux.HandleFunc("GET /api/traces", s.handleTraceList)
+	mux.HandleFunc("GET /api/traces/export", s.handleTraceExport)
 	mux.HandleFunc("GET /api/traces/{id}", s.handleTraceGet)
 	mux.HandleFunc("DELETE /api/traces/{id}", s.handleTraceDelete)
 	mux.HandleFunc("GET /api/metrics/summary", s.handleMetricsSummary)
+	mux.HandleFunc("POST /api/sessions", s.handleSessionCreate)
+	mux.HandleFunc("GET /api/sessions/{id}/history", s.handleSessionHistory)
+	mux.HandleFunc("DELETE /api/sessions/{id}/history", s.handleSessionHistoryDelete)
+	mux.HandleFunc("POST /api/vector/index-batch", s.handleVectorIndexBatch)
+	mux.HandleFunc("POST /api/admin/tenants", s.handleTenantCreate)
 
-	return corsMiddleware(mux)
+	return corsMiddleware(s.authMiddleware(mux))
+}
+
+// newSessionStore persists sessions alongside traces in SQLite. Postgres has
+// no session table, so Postgres deployments keep sessions in memory.
+func newSessionStore(dsn string, ttl time.Duration) (SessionStore, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return store.NewMemorySessionStore(ttl), nil
+	}
+	return store.NewSQLiteSessionStore(dsn, ttl)
 }
 
 func defaultModels() []ModelInfo {
@@ -211,6 +377,36 @@ func defaultTemplates() []PipelineInfo {
 			},
 			Edges: []EdgeInfo{},
 		},
+		templateFromConfig(
+			pipelines.NewMultiQueryRAGPipeline(defaultPrompt+" Answer using the retrieved context.", 3),
+			"Expand the question into several queries and retrieve with each",
+		),
+	}
+}
+
+// templateFromConfig converts a prebuilt pipeline into a template.
+func templateFromConfig(cfg *config.PipelineConfig, description string) PipelineInfo {
+	nodes := make([]NodeInfo, len(cfg.Nodes))
+	for i, n := range cfg.Nodes {
+		nodes[i] = NodeInfo{ID: n.ID, NodeType: n.Type.String(), Prompt: strPtr(n.Prompt), Tools: n.Tools}
+		if n.SystemPrompt != "" {
+			nodes[i].SystemPrompt = strPtr(n.SystemPrompt)
+		}
+	}
+
+	edges := make([]EdgeInfo, len(cfg.Edges))
+	for i, e := range cfg.Edges {
+		from, _ := json.Marshal(e.From.Node)
+		to, _ := json.Marshal(e.To.Node)
+		edges[i] = EdgeInfo{From: from, To: to}
+	}
+
+	return PipelineInfo{
+		ID:          cfg.ID,
+		Name:        cfg.Name,
+		Description: description,
+		Nodes:       nodes,
+		Edges:       edges,
 	}
 }
 
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hubenschmidt/go-fissio/core"
+	"github.com/hubenschmidt/go-fissio/engine"
+	"github.com/hubenschmidt/go-fissio/server/store"
+)
+
+// loadSession returns the session's history as conversation memory. Unknown
+// or expired sessions start empty, so clients may pick their own session IDs.
+func (s *Server) loadSession(ctx context.Context, sessionID string) (*engine.ConversationMemory, error) {
+	mem := engine.NewConversationMemory(s.sessionMaxMessages)
+	sess, err := s.sessions.Get(ctx, sessionID)
+	if errors.Is(err, store.ErrNotFound) {
+		return mem, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range sess.Messages {
+		mem.Add(m.Role, m.Content)
+	}
+	return mem, nil
+}
+
+// saveSession writes mem back as the session's history.
+func (s *Server) saveSession(ctx context.Context, sessionID string, mem *engine.ConversationMemory) error {
+	msgs := mem.Messages()
+	history := make([]HistoryMessage, len(msgs))
+	for i, m := range msgs {
+		history[i] = HistoryMessage{Role: string(m.Role), Content: m.Content}
+	}
+	return s.sessions.Set(context.WithoutCancel(ctx), Session{ID: sessionID, Messages: history})
+}
+
+// appendSession records one user/assistant exchange in mem and persists it.
+func (s *Server) appendSession(ctx context.Context, sessionID string, mem *engine.ConversationMemory, input, output string) error {
+	mem.Add(string(core.RoleUser), input)
+	mem.Add(string(core.RoleAssistant), output)
+	return s.saveSession(ctx, sessionID, mem)
+}
+
+func (s *Server) handleSessionCreate(w http.ResponseWriter, r *http.Request) {
+	id := "sess_" + rand.Text()
+	if err := s.sessions.Set(r.Context(), Session{ID: id}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateSessionResponse{SessionID: id})
+}
+
+func (s *Server) handleSessionHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	sess, err := s.sessions.Get(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	messages := sess.Messages
+	if messages == nil {
+		messages = []HistoryMessage{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SessionHistoryResponse{SessionID: id, Messages: messages})
+}
+
+func (s *Server) handleSessionHistoryDelete(w http.ResponseWriter, r *http.Request) {
+	err := s.sessions.Delete(r.Context(), r.PathValue("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Backup record types
+const (
+	backupTrace    = "trace"
+	backupPipeline = "pipeline"
+)
+
+// backupRecord is one JSONL line of a backup.
+type backupRecord struct {
+	Type     string        `json:"type"`
+	TenantID string        `json:"tenant_id"`
+	Trace    *TraceInfo    `json:"trace,omitempty"`
+	Pipeline *PipelineInfo `json:"pipeline,omitempty"`
+}
+
+// Backup writes every trace and every pipeline version of every tenant to w
+// as JSONL, one record per line tagged with its tenant_id. Both stores must
+// implement TenantLister. Pipeline versions are written oldest first so
+// Restore recreates the same history.
+func Backup(ctx context.Context, traces TraceStore, pipelines PipelineStore, w io.Writer) error {
+	tenants, err := listTenants(ctx, traces, pipelines)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for _, tenantID := range tenants {
+		if err := backupTenant(WithTenant(ctx, tenantID), traces, pipelines, enc); err != nil {
+			return fmt.Errorf("tenant %q: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// BackupTenant is Backup for the context's tenant only.
+func BackupTenant(ctx context.Context, traces TraceStore, pipelines PipelineStore, w io.Writer) error {
+	return backupTenant(ctx, traces, pipelines, json.NewEncoder(w))
+}
+
+// listTenants merges the tenants of both stores.
+func listTenants(ctx context.Context, stores ...any) ([]string, error) {
+	var tenants []string
+	for _, s := range stores {
+		lister, ok := s.(TenantLister)
+		if !ok {
+			return nil, fmt.Errorf("%T cannot list tenants", s)
+		}
+		ids, err := lister.Tenants(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, ids...)
+	}
+	slices.Sort(tenants)
+	return slices.Compact(tenants), nil
+}
+
+func backupTenant(ctx context.Context, traces TraceStore, pipelines PipelineStore, enc *json.Encoder) error {
+	tenantID := TenantFromContext(ctx)
+
+	ts, err := traces.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list traces: %w", err)
+	}
+	for i := range ts {
+		if err := enc.Encode(backupRecord{Type: backupTrace, TenantID: tenantID, Trace: &ts[i]}); err != nil {
+			return fmt.Errorf("write trace %s: %w", ts[i].TraceID, err)
+		}
+	}
+
+	ps, err := pipelines.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list pipelines: %w", err)
+	}
+	for _, p := range ps {
+		versions, err := pipelines.ListVersions(ctx, p.ID)
+		if err != nil {
+			return fmt.Errorf("list versions of pipeline %s: %w", p.ID, err)
+		}
+		for _, v := range versions {
+			pv, err := pipelines.GetVersion(ctx, p.ID, v.Version)
+			if err != nil {
+				return fmt.Errorf("get pipeline %s version %d: %w", p.ID, v.Version, err)
+			}
+			if err := enc.Encode(backupRecord{Type: backupPipeline, TenantID: tenantID, Pipeline: &pv}); err != nil {
+				return fmt.Errorf("write pipeline %s: %w", p.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Restore reads a Backup file from r, adding each trace and saving each
+// pipeline version into the tenant recorded on its line. It returns how many
+// traces and pipeline versions were restored. Traces are replaced by ID;
+// pipeline versions are appended to any existing history.
+func Restore(ctx context.Context, traces TraceStore, pipelines PipelineStore, r io.Reader) (int, int, error) {
+	return restore(ctx, traces, pipelines, r, func(tenantID string) context.Context {
+		return WithTenant(ctx, tenantID)
+	})
+}
+
+// RestoreTenant is Restore into the context's tenant, ignoring the tenant
+// recorded on each line.
+func RestoreTenant(ctx context.Context, traces TraceStore, pipelines PipelineStore, r io.Reader) (int, int, error) {
+	return restore(ctx, traces, pipelines, r, func(string) context.Context {
+		return ctx
+	})
+}
+
+func restore(ctx context.Context, traces TraceStore, pipelines PipelineStore, r io.Reader, tenantCtx func(tenantID string) context.Context) (int, int, error) {
+	dec := json.NewDecoder(r)
+	var nTraces, nPipelines int
+	for {
+		var rec backupRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return nTraces, nPipelines, nil
+		}
+		if err != nil {
+			return nTraces, nPipelines, fmt.Errorf("read record %d: %w", nTraces+nPipelines+1, err)
+		}
+
+		recCtx := tenantCtx(rec.TenantID)
+		switch {
+		case rec.Type == backupTrace && rec.Trace != nil:
+			if err := traces.Add(recCtx, *rec.Trace); err != nil {
+				return nTraces, nPipelines, fmt.Errorf("add trace %s: %w", rec.Trace.TraceID, err)
+			}
+			nTraces++
+		case rec.Type == backupPipeline && rec.Pipeline != nil:
+			if err := pipelines.Save(recCtx, *rec.Pipeline); err != nil {
+				return nTraces, nPipelines, fmt.Errorf("save pipeline %s: %w", rec.Pipeline.ID, err)
+			}
+			nPipelines++
+		default:
+			return nTraces, nPipelines, fmt.Errorf("record %d: unknown type %q", nTraces+nPipelines+1, rec.Type)
+		}
+	}
+}
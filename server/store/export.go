@@ -0,0 +1,126 @@
+package store
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrUnsupportedFormat is returned by Export for unknown formats
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Export formats accepted by TraceStore.Export
+const (
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+// traceWriter streams traces to an io.Writer in one export format.
+type traceWriter interface {
+	Write(t TraceInfo) error
+	Close() error
+}
+
+func newTraceWriter(w io.Writer, format string) (traceWriter, error) {
+	switch format {
+	case ExportJSON:
+		return &jsonTraceWriter{w: w}, nil
+	case ExportNDJSON:
+		return &ndjsonTraceWriter{enc: json.NewEncoder(w)}, nil
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		return &csvTraceWriter{w: cw}, cw.Write(csvHeader)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// jsonTraceWriter writes a single JSON array without buffering all traces.
+type jsonTraceWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonTraceWriter) Write(t TraceInfo) error {
+	sep := ","
+	if j.count == 0 {
+		sep = "["
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("marshal trace: %w", err)
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonTraceWriter) Close() error {
+	end := "]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+type ndjsonTraceWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonTraceWriter) Write(t TraceInfo) error {
+	return n.enc.Encode(t)
+}
+
+func (n *ndjsonTraceWriter) Close() error {
+	return nil
+}
+
+var csvHeader = []string{
+	"trace_id", "pipeline_id", "pipeline_name", "timestamp", "status",
+	"total_elapsed_ms", "total_input_tokens", "total_output_tokens", "estimated_cost_usd",
+	"span_id", "node_id", "node_type", "span_start_time", "span_end_time",
+	"span_input_tokens", "span_output_tokens", "tool_call_count", "iteration_count",
+}
+
+// csvTraceWriter writes one row per span, repeating the trace columns so
+// rows can be grouped by trace_id. Traces without spans get a single row.
+type csvTraceWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvTraceWriter) Write(t TraceInfo) error {
+	trace := []string{
+		t.TraceID, t.PipelineID, t.PipelineName, strconv.FormatInt(t.Timestamp, 10), t.Status,
+		strconv.FormatInt(t.TotalElapsedMs, 10), strconv.Itoa(t.TotalInputTokens), strconv.Itoa(t.TotalOutputTokens),
+		strconv.FormatFloat(t.EstimatedCostUSD, 'f', -1, 64),
+	}
+
+	if len(t.Spans) == 0 {
+		return c.w.Write(append(trace, make([]string, len(csvHeader)-len(trace))...))
+	}
+
+	for _, s := range t.Spans {
+		row := append(trace[:len(trace):len(trace)],
+			s.SpanID, s.NodeID, s.NodeType,
+			strconv.FormatInt(s.StartTime, 10), strconv.FormatInt(s.EndTime, 10),
+			strconv.Itoa(s.InputTokens), strconv.Itoa(s.OutputTokens),
+			strconv.Itoa(s.ToolCallCount), strconv.Itoa(s.IterationCount),
+		)
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvTraceWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
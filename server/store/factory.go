@@ -9,13 +9,15 @@ import (
 // - Empty DSN: SQLite at data/fissio.db
 // - postgres:// or postgresql://: PostgreSQL
 // - Anything else: SQLite at the specified path
-func NewStores(dsn string) (TraceStore, PipelineStore, error) {
+//
+// pg tunes the connection pool and is ignored for SQLite.
+func NewStores(dsn string, pg PostgresConfig) (TraceStore, PipelineStore, error) {
 	if dsn == "" {
 		return NewSQLiteStores("data/fissio.db")
 	}
 
 	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
-		ts, ps, err := NewPostgresStores(dsn)
+		ts, ps, err := NewPostgresStores(dsn, pg)
 		if err != nil {
 			return nil, nil, fmt.Errorf("postgres: %w", err)
 		}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migration is one numbered SQL file, e.g. 003_tenant.sql is version 3.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrationDialect adapts the migration runner to one database.
+type migrationDialect struct {
+	placeholder string                                      // bind parameter for the version, e.g. "?" or "$1"
+	begin       func(ctx context.Context, tx *sql.Tx) error // Optional: runs first in every migration transaction
+	applied     func(err error) bool                        // Optional: reports errors meaning the migration's changes already exist
+}
+
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+
+// loadMigrations reads the *.sql files in dir of fsys, ordered by the number
+// before the first underscore of each name.
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	var ms []migration
+	seen := make(map[int]string)
+	for _, file := range files {
+		name := path.Base(file)
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: name must start with a version number", name)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		ms = append(ms, migration{version: version, name: name, sql: string(data)})
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].version < ms[j].version })
+	return ms, nil
+}
+
+// runMigrations applies the migrations not yet recorded in schema_migrations,
+// each in its own transaction together with its schema_migrations row.
+func runMigrations(ctx context.Context, db *sql.DB, ms []migration, d migrationDialect) error {
+	applied, err := appliedMigrations(ctx, db, d)
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if applied[m.version] {
+			continue
+		}
+		if err := applyMigration(ctx, db, m, d); err != nil {
+			return fmt.Errorf("apply migration %s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// appliedMigrations creates schema_migrations if needed and returns its versions.
+func appliedMigrations(ctx context.Context, db *sql.DB, d migrationDialect) (map[int]bool, error) {
+	tx, err := beginMigration(ctx, db, d)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	return applied, tx.Commit()
+}
+
+// applyMigration runs m and records it. The version is checked again inside
+// the transaction in case another process applied it in the meantime.
+func applyMigration(ctx context.Context, db *sql.DB, m migration, d migrationDialect) error {
+	tx, err := beginMigration(ctx, db, d)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var n int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = "+d.placeholder, m.version).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("check schema_migrations: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil && (d.applied == nil || !d.applied(err)) {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ("+d.placeholder+")", m.version); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+	return tx.Commit()
+}
+
+func beginMigration(ctx context.Context, db *sql.DB, d migrationDialect) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	if d.begin == nil {
+		return tx, nil
+	}
+	if err := d.begin(ctx, tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
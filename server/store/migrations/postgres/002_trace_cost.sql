@@ -0,0 +1,2 @@
+-- Estimated USD cost per trace
+ALTER TABLE traces ADD COLUMN IF NOT EXISTS estimated_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
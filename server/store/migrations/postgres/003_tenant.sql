@@ -0,0 +1,6 @@
+-- Tenant ownership for traces and pipelines
+ALTER TABLE traces ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE pipelines ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_traces_tenant_id ON traces(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_pipelines_tenant_id ON pipelines(tenant_id);
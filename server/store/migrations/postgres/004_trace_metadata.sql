@@ -0,0 +1,2 @@
+-- Free-form trace metadata (e.g. tenant audit info)
+ALTER TABLE traces ADD COLUMN IF NOT EXISTS metadata JSONB DEFAULT 'null';
@@ -0,0 +1,15 @@
+-- Pipeline version history: each save adds a row keyed by (id, version)
+ALTER TABLE pipelines ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE pipelines ADD COLUMN IF NOT EXISTS saved_at BIGINT NOT NULL DEFAULT 0;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.key_column_usage
+        WHERE table_schema = current_schema() AND table_name = 'pipelines'
+            AND constraint_name = 'pipelines_pkey' AND column_name = 'version'
+    ) THEN
+        ALTER TABLE pipelines DROP CONSTRAINT IF EXISTS pipelines_pkey;
+        ALTER TABLE pipelines ADD CONSTRAINT pipelines_pkey PRIMARY KEY (id, version);
+    END IF;
+END $$;
@@ -0,0 +1,2 @@
+-- Estimated USD cost per trace
+ALTER TABLE traces ADD COLUMN estimated_cost_usd REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Tenant ownership for traces
+ALTER TABLE traces ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_traces_tenant_id ON traces(tenant_id);
@@ -0,0 +1,4 @@
+-- Tenant ownership for pipelines
+ALTER TABLE pipelines ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pipelines_tenant_id ON pipelines(tenant_id);
@@ -0,0 +1,10 @@
+-- Persistent chat sessions
+CREATE TABLE IF NOT EXISTS sessions (
+    tenant_id TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL,
+    messages TEXT NOT NULL,
+    updated_at INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, session_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_updated_at ON sessions(updated_at);
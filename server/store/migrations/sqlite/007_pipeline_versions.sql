@@ -0,0 +1,26 @@
+-- Pipeline version history: each save adds a row keyed by (id, version).
+-- SQLite cannot change a primary key in place, so the table is rebuilt. The
+-- first ALTER fails with "duplicate column name" once applied, skipping the rest.
+ALTER TABLE pipelines ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE pipelines ADD COLUMN saved_at INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE pipelines_versioned (
+    id TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    nodes TEXT DEFAULT '[]',
+    edges TEXT DEFAULT '[]',
+    layout TEXT DEFAULT '{}',
+    saved_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (id, version)
+);
+
+INSERT INTO pipelines_versioned (id, version, tenant_id, name, description, nodes, edges, layout, saved_at)
+SELECT id, version, tenant_id, name, description, nodes, edges, layout, saved_at FROM pipelines;
+
+DROP TABLE pipelines;
+ALTER TABLE pipelines_versioned RENAME TO pipelines;
+
+CREATE INDEX IF NOT EXISTS idx_pipelines_tenant_id ON pipelines(tenant_id);
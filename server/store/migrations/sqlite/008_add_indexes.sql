@@ -0,0 +1,3 @@
+-- Composite indexes for tenant-scoped trace listings, newest first
+CREATE INDEX IF NOT EXISTS idx_traces_tenant_timestamp ON traces(tenant_id, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_traces_tenant_pipeline ON traces(tenant_id, pipeline_id, timestamp DESC);
@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hubenschmidt/go-fissio/server/store/migrations"
@@ -13,24 +16,55 @@ import (
 
 // PostgresTraceStore implements TraceStore using PostgreSQL
 type PostgresTraceStore struct {
-	db *sql.DB
+	db    *sql.DB
+	table string // quoted, schema-qualified when PostgresConfig.SchemaPrefix is set
 }
 
 // PostgresPipelineStore implements PipelineStore using PostgreSQL
 type PostgresPipelineStore struct {
-	db *sql.DB
+	db    *sql.DB
+	table string
+}
+
+// PostgresConfig tunes the connection pool shared by the PostgreSQL stores.
+// Zero fields keep their defaults.
+type PostgresConfig struct {
+	MaxOpenConns    int           // Optional: default 25
+	MaxIdleConns    int           // Optional: default 5
+	ConnMaxLifetime time.Duration // Optional: default 5m
+	ConnMaxIdleTime time.Duration // Optional: default unlimited
+
+	// Optional: schema holding this deployment's traces and pipelines tables,
+	// created if missing, so tenants can share a database. Default: the
+	// connection's search_path (usually public).
+	SchemaPrefix string
+}
+
+func (c PostgresConfig) withDefaults() PostgresConfig {
+	if c.MaxOpenConns <= 0 {
+		c.MaxOpenConns = 25
+	}
+	if c.MaxIdleConns <= 0 {
+		c.MaxIdleConns = 5
+	}
+	if c.ConnMaxLifetime <= 0 {
+		c.ConnMaxLifetime = 5 * time.Minute
+	}
+	return c
 }
 
 // NewPostgresStores creates PostgreSQL-backed trace and pipeline stores
-func NewPostgresStores(dsn string) (TraceStore, PipelineStore, error) {
+func NewPostgresStores(dsn string, cfg PostgresConfig) (TraceStore, PipelineStore, error) {
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
 		return nil, nil, fmt.Errorf("open postgres: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	cfg = cfg.withDefaults()
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -40,24 +74,57 @@ func NewPostgresStores(dsn string) (TraceStore, PipelineStore, error) {
 		return nil, nil, fmt.Errorf("ping postgres: %w", err)
 	}
 
-	if err := runPostgresMigrations(db); err != nil {
+	if err := runPostgresMigrations(ctx, db, cfg.SchemaPrefix); err != nil {
 		db.Close()
 		return nil, nil, fmt.Errorf("run migrations: %w", err)
 	}
 
-	return &PostgresTraceStore{db: db}, &PostgresPipelineStore{db: db}, nil
+	return &PostgresTraceStore{db: db, table: qualifyTable(cfg.SchemaPrefix, "traces")},
+		&PostgresPipelineStore{db: db, table: qualifyTable(cfg.SchemaPrefix, "pipelines")}, nil
 }
 
-func runPostgresMigrations(db *sql.DB) error {
-	data, err := migrations.Postgres.ReadFile("postgres/001_init.sql")
+// pgMigrationLock is the advisory lock key that serializes migrations across
+// servers starting at the same time.
+const pgMigrationLock = 0x66697373696f // "fissio"
+
+// runPostgresMigrations applies the unapplied migrations. With a schema, the
+// schema is created and SET LOCAL search_path points the unqualified table
+// names in the migrations, and schema_migrations, at it.
+func runPostgresMigrations(ctx context.Context, db *sql.DB, schema string) error {
+	ms, err := loadMigrations(migrations.Postgres, "postgres")
 	if err != nil {
-		return fmt.Errorf("read migration: %w", err)
+		return err
 	}
-	_, err = db.Exec(string(data))
-	if err != nil {
-		return fmt.Errorf("exec migration: %w", err)
+	return runMigrations(ctx, db, ms, migrationDialect{
+		placeholder: "$1",
+		begin: func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", pgMigrationLock); err != nil {
+				return fmt.Errorf("lock migrations: %w", err)
+			}
+			if schema == "" {
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+quoteIdent(schema)); err != nil {
+				return fmt.Errorf("create schema: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, "SET LOCAL search_path TO "+quoteIdent(schema)); err != nil {
+				return fmt.Errorf("set search_path: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+// qualifyTable returns table, prefixed with its quoted schema if one is set.
+func qualifyTable(schema, table string) string {
+	if schema == "" {
+		return table
 	}
-	return nil
+	return quoteIdent(schema) + "." + table
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
 
 // TraceStore implementation
@@ -67,13 +134,17 @@ func (s *PostgresTraceStore) Add(ctx context.Context, t TraceInfo) error {
 	if err != nil {
 		return fmt.Errorf("marshal spans: %w", err)
 	}
+	metadata, err := json.Marshal(t.Metadata)
+	if err != nil {
+		return fmt.Errorf("marshal metadata: %w", err)
+	}
 
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO traces (
-			trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO `+s.table+` (
+			trace_id, tenant_id, pipeline_id, pipeline_name, timestamp, input, output,
 			total_elapsed_ms, total_input_tokens, total_output_tokens,
-			total_tool_calls, status, spans
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			total_tool_calls, estimated_cost_usd, status, spans, metadata
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		ON CONFLICT (trace_id) DO UPDATE SET
 			pipeline_id = EXCLUDED.pipeline_id,
 			pipeline_name = EXCLUDED.pipeline_name,
@@ -84,30 +155,33 @@ func (s *PostgresTraceStore) Add(ctx context.Context, t TraceInfo) error {
 			total_input_tokens = EXCLUDED.total_input_tokens,
 			total_output_tokens = EXCLUDED.total_output_tokens,
 			total_tool_calls = EXCLUDED.total_tool_calls,
+			estimated_cost_usd = EXCLUDED.estimated_cost_usd,
 			status = EXCLUDED.status,
-			spans = EXCLUDED.spans`,
-		t.TraceID, t.PipelineID, t.PipelineName, t.Timestamp, t.Input, t.Output,
+			spans = EXCLUDED.spans,
+			metadata = EXCLUDED.metadata
+		WHERE traces.tenant_id = EXCLUDED.tenant_id`,
+		t.TraceID, TenantFromContext(ctx), t.PipelineID, t.PipelineName, t.Timestamp, t.Input, t.Output,
 		t.TotalElapsedMs, t.TotalInputTokens, t.TotalOutputTokens,
-		t.TotalToolCalls, t.Status, spans,
+		t.TotalToolCalls, t.EstimatedCostUSD, t.Status, spans, metadata,
 	)
 	if err != nil {
 		return fmt.Errorf("insert trace: %w", err)
 	}
-	return nil
+	return checkSaved(res)
 }
 
 func (s *PostgresTraceStore) Get(ctx context.Context, id string) (TraceInfo, error) {
 	var t TraceInfo
-	var spansJSON []byte
+	var spansJSON, metadataJSON []byte
 
 	err := s.db.QueryRowContext(ctx, `
 		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
 			   total_elapsed_ms, total_input_tokens, total_output_tokens,
-			   total_tool_calls, status, spans
-		FROM traces WHERE trace_id = $1`, id).Scan(
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM `+s.table+` WHERE trace_id = $1 AND tenant_id = $2`, id, TenantFromContext(ctx)).Scan(
 		&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
 		&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
-		&t.TotalToolCalls, &t.Status, &spansJSON,
+		&t.TotalToolCalls, &t.EstimatedCostUSD, &t.Status, &spansJSON, &metadataJSON,
 	)
 	if err == sql.ErrNoRows {
 		return t, ErrNotFound
@@ -119,6 +193,9 @@ func (s *PostgresTraceStore) Get(ctx context.Context, id string) (TraceInfo, err
 	if err := json.Unmarshal(spansJSON, &t.Spans); err != nil {
 		return t, fmt.Errorf("unmarshal spans: %w", err)
 	}
+	if err := json.Unmarshal(metadataJSON, &t.Metadata); err != nil {
+		return t, fmt.Errorf("unmarshal metadata: %w", err)
+	}
 	return t, nil
 }
 
@@ -126,40 +203,155 @@ func (s *PostgresTraceStore) List(ctx context.Context) ([]TraceInfo, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
 			   total_elapsed_ms, total_input_tokens, total_output_tokens,
-			   total_tool_calls, status, spans
-		FROM traces ORDER BY timestamp DESC`)
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM `+s.table+` WHERE tenant_id = $1 ORDER BY timestamp DESC`, TenantFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query traces: %w", err)
 	}
 	defer rows.Close()
+	return scanPostgresTraces(rows)
+}
+
+func (s *PostgresTraceStore) Query(ctx context.Context, q TraceQuery) (TracePage, error) {
+	where := "tenant_id = $1"
+	args := []any{TenantFromContext(ctx)}
+	if q.PipelineID != "" {
+		args = append(args, q.PipelineID)
+		where += fmt.Sprintf(" AND pipeline_id = $%d", len(args))
+	}
+
+	var page TracePage
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+s.table+" WHERE "+where, args...).Scan(&page.Total); err != nil {
+		return page, fmt.Errorf("count traces: %w", err)
+	}
+
+	var limit any // NULL means no limit
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	args = append(args, limit, q.Offset)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+			   total_elapsed_ms, total_input_tokens, total_output_tokens,
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM `+s.table+` WHERE `+where+fmt.Sprintf(` ORDER BY timestamp DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return page, fmt.Errorf("query traces: %w", err)
+	}
+	defer rows.Close()
 
+	page.Traces, err = scanPostgresTraces(rows)
+	return page, err
+}
+
+func scanPostgresTraces(rows *sql.Rows) ([]TraceInfo, error) {
 	var traces []TraceInfo
 	for rows.Next() {
-		var t TraceInfo
-		var spansJSON []byte
-		if err := rows.Scan(
-			&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
-			&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
-			&t.TotalToolCalls, &t.Status, &spansJSON,
-		); err != nil {
-			return nil, fmt.Errorf("scan trace: %w", err)
-		}
-		if err := json.Unmarshal(spansJSON, &t.Spans); err != nil {
-			return nil, fmt.Errorf("unmarshal spans: %w", err)
+		t, err := scanPostgresTrace(rows)
+		if err != nil {
+			return nil, err
 		}
 		traces = append(traces, t)
 	}
 	return traces, rows.Err()
 }
 
+func scanPostgresTrace(rows *sql.Rows) (TraceInfo, error) {
+	var t TraceInfo
+	var spansJSON, metadataJSON []byte
+	if err := rows.Scan(
+		&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
+		&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
+		&t.TotalToolCalls, &t.EstimatedCostUSD, &t.Status, &spansJSON, &metadataJSON,
+	); err != nil {
+		return t, fmt.Errorf("scan trace: %w", err)
+	}
+	if err := json.Unmarshal(spansJSON, &t.Spans); err != nil {
+		return t, fmt.Errorf("unmarshal spans: %w", err)
+	}
+	if err := json.Unmarshal(metadataJSON, &t.Metadata); err != nil {
+		return t, fmt.Errorf("unmarshal metadata: %w", err)
+	}
+	return t, nil
+}
+
+// Export streams the tenant's traces to w as rows are read, oldest first.
+func (s *PostgresTraceStore) Export(ctx context.Context, w io.Writer, format string) error {
+	tw, err := newTraceWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+			   total_elapsed_ms, total_input_tokens, total_output_tokens,
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM `+s.table+` WHERE tenant_id = $1 ORDER BY timestamp`, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("query traces: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanPostgresTrace(rows)
+		if err != nil {
+			return err
+		}
+		if err := tw.Write(t); err != nil {
+			return fmt.Errorf("write trace: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Tenants lists every tenant with traces, in order.
+func (s *PostgresTraceStore) Tenants(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT tenant_id FROM "+s.table+" ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("query trace tenants: %w", err)
+	}
+	return scanTenants(rows)
+}
+
 func (s *PostgresTraceStore) Delete(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM traces WHERE trace_id = $1`, id)
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE trace_id = $1 AND tenant_id = $2`, id, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("delete trace: %w", err)
 	}
 	return nil
 }
 
+func (s *PostgresTraceStore) Prune(ctx context.Context, policy RetentionPolicy) (int, error) {
+	var removed int64
+	if policy.MaxAgeDays > 0 {
+		res, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE timestamp < $1`, policy.cutoffMillis(time.Now()))
+		if err != nil {
+			return 0, fmt.Errorf("prune traces by age: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	if policy.MaxTraces > 0 {
+		res, err := s.db.ExecContext(ctx, `
+			DELETE FROM `+s.table+` WHERE trace_id IN (
+				SELECT trace_id FROM (
+					SELECT trace_id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY timestamp DESC) AS row_num
+					FROM `+s.table+`
+				) ranked WHERE row_num > $1
+			)`, policy.MaxTraces)
+		if err != nil {
+			return int(removed), fmt.Errorf("prune traces by count: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	return int(removed), nil
+}
+
 func (s *PostgresTraceStore) Summary(ctx context.Context) (MetricsSummary, error) {
 	var m MetricsSummary
 	err := s.db.QueryRowContext(ctx, `
@@ -168,17 +360,35 @@ func (s *PostgresTraceStore) Summary(ctx context.Context) (MetricsSummary, error
 			COALESCE(SUM(total_input_tokens), 0),
 			COALESCE(SUM(total_output_tokens), 0),
 			COALESCE(SUM(total_tool_calls), 0),
-			COALESCE(AVG(total_elapsed_ms), 0)
-		FROM traces`).Scan(
+			COALESCE(AVG(total_elapsed_ms), 0),
+			COALESCE(SUM(estimated_cost_usd), 0)
+		FROM `+s.table+` WHERE tenant_id = $1`, TenantFromContext(ctx)).Scan(
 		&m.TotalTraces, &m.TotalInputTokens, &m.TotalOutputTokens,
-		&m.TotalToolCalls, &m.AvgLatencyMs,
+		&m.TotalToolCalls, &m.AvgLatencyMs, &m.EstimatedCostUSD,
 	)
 	if err != nil {
 		return m, fmt.Errorf("query summary: %w", err)
 	}
+	m.TotalCostUSD = m.EstimatedCostUSD
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pipeline_id, COALESCE(SUM(estimated_cost_usd), 0)
+		FROM `+s.table+` WHERE tenant_id = $1
+		GROUP BY pipeline_id`, TenantFromContext(ctx))
+	if err != nil {
+		return m, fmt.Errorf("query cost by pipeline: %w", err)
+	}
+	if m.CostByPipeline, err = scanCostByPipeline(rows); err != nil {
+		return m, err
+	}
 	return m, nil
 }
 
+// HealthCheck pings the database.
+func (s *PostgresTraceStore) HealthCheck(ctx context.Context) error {
+	return pingPostgres(ctx, s.db)
+}
+
 func (s *PostgresTraceStore) Close() error {
 	return s.db.Close()
 }
@@ -199,31 +409,37 @@ func (s *PostgresPipelineStore) Save(ctx context.Context, p PipelineInfo) error
 		return fmt.Errorf("marshal layout: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO pipelines (id, name, description, nodes, edges, layout)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (id) DO UPDATE SET
-			name = EXCLUDED.name,
-			description = EXCLUDED.description,
-			nodes = EXCLUDED.nodes,
-			edges = EXCLUDED.edges,
-			layout = EXCLUDED.layout`,
-		p.ID, p.Name, p.Description, nodes, edges, layout,
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO `+s.table+` (id, version, tenant_id, name, description, nodes, edges, layout, saved_at)
+		SELECT $1::text, (SELECT COALESCE(MAX(version), 0) + 1 FROM `+s.table+` WHERE id = $1), $2::text, $3::text, $4::text,
+			$5::jsonb, $6::jsonb, $7::jsonb, $8::bigint
+		WHERE NOT EXISTS (SELECT 1 FROM `+s.table+` WHERE id = $1 AND tenant_id <> $2)`,
+		p.ID, TenantFromContext(ctx), p.Name, p.Description, nodes, edges, layout, time.Now().UnixMilli(),
 	)
 	if err != nil {
 		return fmt.Errorf("insert pipeline: %w", err)
 	}
-	return nil
+	return checkSaved(res)
 }
 
 func (s *PostgresPipelineStore) Get(ctx context.Context, id string) (PipelineInfo, error) {
+	return s.get(ctx, `
+		SELECT id, version, name, description, nodes, edges, layout
+		FROM `+s.table+` WHERE id = $1 AND tenant_id = $2 ORDER BY version DESC LIMIT 1`, id, TenantFromContext(ctx))
+}
+
+func (s *PostgresPipelineStore) GetVersion(ctx context.Context, id string, version int) (PipelineInfo, error) {
+	return s.get(ctx, `
+		SELECT id, version, name, description, nodes, edges, layout
+		FROM `+s.table+` WHERE id = $1 AND tenant_id = $2 AND version = $3`, id, TenantFromContext(ctx), version)
+}
+
+func (s *PostgresPipelineStore) get(ctx context.Context, query string, args ...any) (PipelineInfo, error) {
 	var p PipelineInfo
 	var nodesJSON, edgesJSON, layoutJSON []byte
 
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, name, description, nodes, edges, layout
-		FROM pipelines WHERE id = $1`, id).Scan(
-		&p.ID, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON,
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
+		&p.ID, &p.Version, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON,
 	)
 	if err == sql.ErrNoRows {
 		return p, ErrNotFound
@@ -246,8 +462,8 @@ func (s *PostgresPipelineStore) Get(ctx context.Context, id string) (PipelineInf
 
 func (s *PostgresPipelineStore) List(ctx context.Context) ([]PipelineInfo, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, name, description, nodes, edges, layout
-		FROM pipelines ORDER BY name`)
+		SELECT DISTINCT ON (id) id, version, name, description, nodes, edges, layout
+		FROM `+s.table+` WHERE tenant_id = $1 ORDER BY id, version DESC`, TenantFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query pipelines: %w", err)
 	}
@@ -257,7 +473,7 @@ func (s *PostgresPipelineStore) List(ctx context.Context) ([]PipelineInfo, error
 	for rows.Next() {
 		var p PipelineInfo
 		var nodesJSON, edgesJSON, layoutJSON []byte
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON); err != nil {
+		if err := rows.Scan(&p.ID, &p.Version, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON); err != nil {
 			return nil, fmt.Errorf("scan pipeline: %w", err)
 		}
 		if err := json.Unmarshal(nodesJSON, &p.Nodes); err != nil {
@@ -271,17 +487,52 @@ func (s *PostgresPipelineStore) List(ctx context.Context) ([]PipelineInfo, error
 		}
 		pipelines = append(pipelines, p)
 	}
-	return pipelines, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pipelines, func(i, j int) bool { return pipelines[i].Name < pipelines[j].Name })
+	return pipelines, nil
+}
+
+func (s *PostgresPipelineStore) ListVersions(ctx context.Context, id string) ([]PipelineVersion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, version, name, saved_at
+		FROM `+s.table+` WHERE id = $1 AND tenant_id = $2 ORDER BY version`, id, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline versions: %w", err)
+	}
+	return scanPipelineVersions(rows)
+}
+
+// Tenants lists every tenant with pipelines, in order.
+func (s *PostgresPipelineStore) Tenants(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT tenant_id FROM "+s.table+" ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline tenants: %w", err)
+	}
+	return scanTenants(rows)
 }
 
 func (s *PostgresPipelineStore) Delete(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM pipelines WHERE id = $1`, id)
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE id = $1 AND tenant_id = $2`, id, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("delete pipeline: %w", err)
 	}
 	return nil
 }
 
+// HealthCheck pings the database.
+func (s *PostgresPipelineStore) HealthCheck(ctx context.Context) error {
+	return pingPostgres(ctx, s.db)
+}
+
 func (s *PostgresPipelineStore) Close() error {
 	return s.db.Close()
 }
+
+func pingPostgres(ctx context.Context, db *sql.DB) error {
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping postgres: %w", err)
+	}
+	return nil
+}
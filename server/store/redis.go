@@ -0,0 +1,341 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTraceOption configures a RedisTraceStore.
+type RedisTraceOption func(*RedisTraceStore)
+
+// WithRedisMaxLen sets how many stream entries are kept, trimmed
+// approximately on each Add (default: 10000).
+func WithRedisMaxLen(n int64) RedisTraceOption {
+	return func(s *RedisTraceStore) {
+		if n > 0 {
+			s.maxLen = n
+		}
+	}
+}
+
+// WithRedisScanLimit sets how many of a tenant's newest traces List, Query,
+// Summary and Export consider (default: 1000).
+func WithRedisScanLimit(n int) RedisTraceOption {
+	return func(s *RedisTraceStore) {
+		if n > 0 {
+			s.scanLimit = n
+		}
+	}
+}
+
+// RedisTraceStore implements TraceStore on a Redis stream. Add appends with
+// XADD and trims with XTRIM in one round trip, so writes never wait on each
+// other; reads scan the stream newest first. Re-adding a trace appends a new
+// entry that shadows the old one.
+type RedisTraceStore struct {
+	client    *redis.Client
+	streamKey string
+	maxLen    int64
+	scanLimit int
+}
+
+// redisScanBatch is the number of entries fetched per XREVRANGE while scanning.
+const redisScanBatch = 500
+
+// NewRedisTraceStore stores traces in the stream at streamKey, shared by all
+// tenants. The client belongs to the caller; Close does not close it.
+func NewRedisTraceStore(client *redis.Client, streamKey string, opts ...RedisTraceOption) TraceStore {
+	s := &RedisTraceStore{client: client, streamKey: streamKey, maxLen: 10000, scanLimit: 1000}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *RedisTraceStore) Add(ctx context.Context, t TraceInfo) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("marshal trace: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.streamKey,
+		Values: map[string]any{"trace_id": t.TraceID, "tenant_id": TenantFromContext(ctx), "trace": data},
+	})
+	pipe.XTrimMaxLenApprox(ctx, s.streamKey, s.maxLen, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("add trace: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisTraceStore) Get(ctx context.Context, id string) (TraceInfo, error) {
+	var t TraceInfo
+	found := false
+	err := s.scan(ctx, func(msg redis.XMessage) (bool, error) {
+		if !s.matches(ctx, msg, id) {
+			return true, nil
+		}
+		var err error
+		t, err = decodeRedisTrace(msg)
+		found = true
+		return false, err
+	})
+	if err != nil {
+		return t, err
+	}
+	if !found {
+		return t, ErrNotFound
+	}
+	return t, nil
+}
+
+// List returns the tenant's newest traces, up to ListLimit, newest first.
+func (s *RedisTraceStore) List(ctx context.Context) ([]TraceInfo, error) {
+	return s.recent(ctx, "", s.scanLimit)
+}
+
+// ListLimit returns the scan limit, which caps List, Summary and Export.
+func (s *RedisTraceStore) ListLimit() int {
+	return s.scanLimit
+}
+
+// Query pages through all of the tenant's traces, ignoring the scan limit so
+// that Total is exact; it reads the whole stream.
+func (s *RedisTraceStore) Query(ctx context.Context, q TraceQuery) (TracePage, error) {
+	traces, err := s.recent(ctx, q.PipelineID, 0)
+	if err != nil {
+		return TracePage{}, err
+	}
+
+	page := TracePage{Total: len(traces)}
+	start := min(q.Offset, len(traces))
+	end := len(traces)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	page.Traces = traces[start:end]
+	return page, nil
+}
+
+// Export writes the traces List would return, oldest first.
+func (s *RedisTraceStore) Export(ctx context.Context, w io.Writer, format string) error {
+	tw, err := newTraceWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	traces, err := s.recent(ctx, "", s.scanLimit)
+	if err != nil {
+		return err
+	}
+	for _, t := range slices.Backward(traces) {
+		if err := tw.Write(t); err != nil {
+			return fmt.Errorf("write trace: %w", err)
+		}
+	}
+	return tw.Close()
+}
+
+// Tenants lists every tenant with traces in the stream, in order.
+func (s *RedisTraceStore) Tenants(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	err := s.scan(ctx, func(msg redis.XMessage) (bool, error) {
+		tenantID, _ := msg.Values["tenant_id"].(string)
+		seen[tenantID] = true
+		return true, nil
+	})
+	return slices.Sorted(maps.Keys(seen)), err
+}
+
+// Delete removes every stream entry recorded for the trace.
+func (s *RedisTraceStore) Delete(ctx context.Context, id string) error {
+	var entryIDs []string
+	err := s.scan(ctx, func(msg redis.XMessage) (bool, error) {
+		if s.matches(ctx, msg, id) {
+			entryIDs = append(entryIDs, msg.ID)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(entryIDs) == 0 {
+		return nil
+	}
+	if err := s.client.XDel(ctx, s.streamKey, entryIDs...).Err(); err != nil {
+		return fmt.Errorf("delete trace: %w", err)
+	}
+	return nil
+}
+
+// Prune trims the stream by age, then deletes the entries of each tenant's
+// traces beyond its newest MaxTraces. Age and recency are judged by when each
+// entry was added, which stream IDs encode, rather than by trace timestamp.
+func (s *RedisTraceStore) Prune(ctx context.Context, policy RetentionPolicy) (int, error) {
+	var removed int64
+	if policy.MaxAgeDays > 0 {
+		minID := strconv.FormatInt(policy.cutoffMillis(time.Now()), 10)
+		n, err := s.client.XTrimMinID(ctx, s.streamKey, minID).Result()
+		if err != nil {
+			return 0, fmt.Errorf("prune traces by age: %w", err)
+		}
+		removed += n
+	}
+	if policy.MaxTraces > 0 {
+		n, err := s.trimTenants(ctx, policy.MaxTraces)
+		removed += n
+		if err != nil {
+			return int(removed), fmt.Errorf("prune traces by count: %w", err)
+		}
+	}
+	return int(removed), nil
+}
+
+// trimTenants deletes every entry of the traces beyond each tenant's newest
+// maxTraces and returns how many entries were removed.
+func (s *RedisTraceStore) trimTenants(ctx context.Context, maxTraces int) (int64, error) {
+	kept := make(map[string]map[string]bool) // tenant ID -> trace IDs kept
+	var stale []string
+	err := s.scan(ctx, func(msg redis.XMessage) (bool, error) {
+		tenantID, _ := msg.Values["tenant_id"].(string)
+		traceID, _ := msg.Values["trace_id"].(string)
+		traces := kept[tenantID]
+		if traces == nil {
+			traces = make(map[string]bool)
+			kept[tenantID] = traces
+		}
+		if !traces[traceID] && len(traces) < maxTraces {
+			traces[traceID] = true
+		}
+		if !traces[traceID] {
+			stale = append(stale, msg.ID)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var removed int64
+	for batch := range slices.Chunk(stale, redisScanBatch) {
+		n, err := s.client.XDel(ctx, s.streamKey, batch...).Result()
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+// Summary aggregates the traces List would return.
+func (s *RedisTraceStore) Summary(ctx context.Context) (MetricsSummary, error) {
+	m := MetricsSummary{CostByPipeline: make(map[string]float64)}
+	traces, err := s.recent(ctx, "", s.scanLimit)
+	if err != nil {
+		return m, err
+	}
+
+	var totalElapsed int64
+	for _, t := range traces {
+		m.TotalInputTokens += t.TotalInputTokens
+		m.TotalOutputTokens += t.TotalOutputTokens
+		m.TotalToolCalls += t.TotalToolCalls
+		m.EstimatedCostUSD += t.EstimatedCostUSD
+		m.CostByPipeline[t.PipelineID] += t.EstimatedCostUSD
+		totalElapsed += t.TotalElapsedMs
+	}
+	m.TotalTraces = len(traces)
+	m.Truncated = len(traces) >= s.scanLimit
+	m.TotalCostUSD = m.EstimatedCostUSD
+	if len(traces) > 0 {
+		m.AvgLatencyMs = float64(totalElapsed) / float64(len(traces))
+	}
+	return m, nil
+}
+
+// HealthCheck pings the Redis server.
+func (s *RedisTraceStore) HealthCheck(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisTraceStore) Close() error {
+	return nil
+}
+
+// recent returns the tenant's newest traces, up to limit (0 = all) and
+// optionally for one pipeline, ordered by timestamp descending. Only the
+// newest entry of a re-added trace is kept. Other tenants' entries do not
+// count toward the limit, so a quiet tenant may read the whole stream, which
+// maxLen bounds.
+func (s *RedisTraceStore) recent(ctx context.Context, pipelineID string, limit int) ([]TraceInfo, error) {
+	tenantID := TenantFromContext(ctx)
+	seen := make(map[string]bool)
+	var traces []TraceInfo
+	err := s.scan(ctx, func(msg redis.XMessage) (bool, error) {
+		traceID, _ := msg.Values["trace_id"].(string)
+		if msg.Values["tenant_id"] != tenantID || seen[traceID] {
+			return true, nil
+		}
+		seen[traceID] = true
+
+		t, err := decodeRedisTrace(msg)
+		if err != nil {
+			return false, err
+		}
+		if pipelineID == "" || t.PipelineID == pipelineID {
+			traces = append(traces, t)
+		}
+		return limit == 0 || len(seen) < limit, nil
+	})
+	slices.SortStableFunc(traces, func(a, b TraceInfo) int {
+		return cmp.Compare(b.Timestamp, a.Timestamp)
+	})
+	return traces, err
+}
+
+// scan calls fn for each entry, newest first, until fn returns false or an
+// error or the stream is exhausted.
+func (s *RedisTraceStore) scan(ctx context.Context, fn func(msg redis.XMessage) (bool, error)) error {
+	end := "+"
+	for {
+		msgs, err := s.client.XRevRangeN(ctx, s.streamKey, end, "-", redisScanBatch).Result()
+		if err != nil {
+			return fmt.Errorf("read traces: %w", err)
+		}
+		for _, msg := range msgs {
+			more, err := fn(msg)
+			if err != nil || !more {
+				return err
+			}
+		}
+		if len(msgs) < redisScanBatch {
+			return nil
+		}
+		end = "(" + msgs[len(msgs)-1].ID
+	}
+}
+
+// matches reports whether msg records trace id for the context's tenant.
+func (s *RedisTraceStore) matches(ctx context.Context, msg redis.XMessage, id string) bool {
+	return msg.Values["trace_id"] == id && msg.Values["tenant_id"] == TenantFromContext(ctx)
+}
+
+func decodeRedisTrace(msg redis.XMessage) (TraceInfo, error) {
+	var t TraceInfo
+	data, _ := msg.Values["trace"].(string)
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return t, fmt.Errorf("unmarshal trace %s: %w", msg.ID, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SessionMessage is one turn of a chat session
+type SessionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Session is a chat conversation persisted between requests
+type Session struct {
+	ID        string           `json:"session_id"`
+	Messages  []SessionMessage `json:"messages"`
+	UpdatedAt int64            `json:"updated_at"` // unix millis, set by Set
+}
+
+// SessionStore defines the interface for chat session persistence.
+// Sessions are scoped to the tenant carried by ctx.
+type SessionStore interface {
+	// Get returns ErrNotFound for missing or expired sessions
+	Get(ctx context.Context, id string) (Session, error)
+	// Set creates or replaces a session and refreshes its expiry
+	Set(ctx context.Context, s Session) error
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+// expired reports whether a session last updated at updatedAt has outlived ttl.
+func expired(updatedAt int64, ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && updatedAt < now.Add(-ttl).UnixMilli()
+}
+
+// MemorySessionStore implements SessionStore in process memory
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	ttl      time.Duration
+}
+
+// NewMemorySessionStore creates a session store whose sessions expire after
+// ttl without updates (0 = never).
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session), ttl: ttl}
+}
+
+func memorySessionKey(ctx context.Context, id string) string {
+	return TenantFromContext(ctx) + "/" + id
+}
+
+func (s *MemorySessionStore) Get(ctx context.Context, id string) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memorySessionKey(ctx, id)
+	sess, ok := s.sessions[key]
+	if !ok {
+		return Session{}, ErrNotFound
+	}
+	if expired(sess.UpdatedAt, s.ttl, time.Now()) {
+		delete(s.sessions, key)
+		return Session{}, ErrNotFound
+	}
+	sess.Messages = append([]SessionMessage(nil), sess.Messages...)
+	return sess, nil
+}
+
+func (s *MemorySessionStore) Set(ctx context.Context, sess Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess.Messages = append([]SessionMessage(nil), sess.Messages...)
+	sess.UpdatedAt = time.Now().UnixMilli()
+	s.sessions[memorySessionKey(ctx, sess.ID)] = sess
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memorySessionKey(ctx, id)
+	if _, ok := s.sessions[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.sessions, key)
+	return nil
+}
+
+func (s *MemorySessionStore) Close() error {
+	return nil
+}
@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/hubenschmidt/go-fissio/server/store/migrations"
 	_ "modernc.org/sqlite"
@@ -22,8 +25,32 @@ type SQLitePipelineStore struct {
 	db *sql.DB
 }
 
+// SQLiteSessionStore implements SessionStore using SQLite
+type SQLiteSessionStore struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
 // NewSQLiteStores creates SQLite-backed trace and pipeline stores
 func NewSQLiteStores(dsn string) (TraceStore, PipelineStore, error) {
+	db, err := openSQLite(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SQLiteTraceStore{db: db}, &SQLitePipelineStore{db: db}, nil
+}
+
+// NewSQLiteSessionStore creates a SQLite-backed session store whose sessions
+// expire after ttl without updates (0 = never)
+func NewSQLiteSessionStore(dsn string, ttl time.Duration) (*SQLiteSessionStore, error) {
+	db, err := openSQLite(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteSessionStore{db: db, ttl: ttl}, nil
+}
+
+func openSQLite(dsn string) (*sql.DB, error) {
 	if dsn == "" {
 		dsn = "data/fissio.db"
 	}
@@ -31,33 +58,36 @@ func NewSQLiteStores(dsn string) (TraceStore, PipelineStore, error) {
 	dir := filepath.Dir(dsn)
 	if dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return nil, nil, fmt.Errorf("create data directory: %w", err)
+			return nil, fmt.Errorf("create data directory: %w", err)
 		}
 	}
 
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
-		return nil, nil, fmt.Errorf("open sqlite: %w", err)
+		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
 
 	if err := runSQLiteMigrations(db); err != nil {
 		db.Close()
-		return nil, nil, fmt.Errorf("run migrations: %w", err)
+		return nil, fmt.Errorf("run migrations: %w", err)
 	}
-
-	return &SQLiteTraceStore{db: db}, &SQLitePipelineStore{db: db}, nil
+	return db, nil
 }
 
 func runSQLiteMigrations(db *sql.DB) error {
-	data, err := migrations.SQLite.ReadFile("sqlite/001_init.sql")
+	ms, err := loadMigrations(migrations.SQLite, "sqlite")
 	if err != nil {
-		return fmt.Errorf("read migration: %w", err)
+		return err
 	}
-	_, err = db.Exec(string(data))
-	if err != nil {
-		return fmt.Errorf("exec migration: %w", err)
-	}
-	return nil
+	return runMigrations(context.Background(), db, ms, migrationDialect{
+		placeholder: "?",
+		// Databases created before schema_migrations have no record of their
+		// migrations. SQLite has no ADD COLUMN IF NOT EXISTS, so re-adding a
+		// column marks the migration as applied.
+		applied: func(err error) bool {
+			return strings.Contains(err.Error(), "duplicate column name")
+		},
+	})
 }
 
 // TraceStore implementation
@@ -67,35 +97,54 @@ func (s *SQLiteTraceStore) Add(ctx context.Context, t TraceInfo) error {
 	if err != nil {
 		return fmt.Errorf("marshal spans: %w", err)
 	}
+	metadata, err := json.Marshal(t.Metadata)
+	if err != nil {
+		return fmt.Errorf("marshal metadata: %w", err)
+	}
 
-	_, err = s.db.ExecContext(ctx, `
-		INSERT OR REPLACE INTO traces (
-			trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO traces (
+			trace_id, tenant_id, pipeline_id, pipeline_name, timestamp, input, output,
 			total_elapsed_ms, total_input_tokens, total_output_tokens,
-			total_tool_calls, status, spans
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		t.TraceID, t.PipelineID, t.PipelineName, t.Timestamp, t.Input, t.Output,
+			total_tool_calls, estimated_cost_usd, status, spans, metadata
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(trace_id) DO UPDATE SET
+			pipeline_id = excluded.pipeline_id,
+			pipeline_name = excluded.pipeline_name,
+			timestamp = excluded.timestamp,
+			input = excluded.input,
+			output = excluded.output,
+			total_elapsed_ms = excluded.total_elapsed_ms,
+			total_input_tokens = excluded.total_input_tokens,
+			total_output_tokens = excluded.total_output_tokens,
+			total_tool_calls = excluded.total_tool_calls,
+			estimated_cost_usd = excluded.estimated_cost_usd,
+			status = excluded.status,
+			spans = excluded.spans,
+			metadata = excluded.metadata
+		WHERE traces.tenant_id = excluded.tenant_id`,
+		t.TraceID, TenantFromContext(ctx), t.PipelineID, t.PipelineName, t.Timestamp, t.Input, t.Output,
 		t.TotalElapsedMs, t.TotalInputTokens, t.TotalOutputTokens,
-		t.TotalToolCalls, t.Status, string(spans),
+		t.TotalToolCalls, t.EstimatedCostUSD, t.Status, string(spans), string(metadata),
 	)
 	if err != nil {
 		return fmt.Errorf("insert trace: %w", err)
 	}
-	return nil
+	return checkSaved(res)
 }
 
 func (s *SQLiteTraceStore) Get(ctx context.Context, id string) (TraceInfo, error) {
 	var t TraceInfo
-	var spansJSON string
+	var spansJSON, metadataJSON string
 
 	err := s.db.QueryRowContext(ctx, `
 		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
 			   total_elapsed_ms, total_input_tokens, total_output_tokens,
-			   total_tool_calls, status, spans
-		FROM traces WHERE trace_id = ?`, id).Scan(
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM traces WHERE trace_id = ? AND tenant_id = ?`, id, TenantFromContext(ctx)).Scan(
 		&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
 		&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
-		&t.TotalToolCalls, &t.Status, &spansJSON,
+		&t.TotalToolCalls, &t.EstimatedCostUSD, &t.Status, &spansJSON, &metadataJSON,
 	)
 	if err == sql.ErrNoRows {
 		return t, ErrNotFound
@@ -107,6 +156,9 @@ func (s *SQLiteTraceStore) Get(ctx context.Context, id string) (TraceInfo, error
 	if err := json.Unmarshal([]byte(spansJSON), &t.Spans); err != nil {
 		return t, fmt.Errorf("unmarshal spans: %w", err)
 	}
+	if err := json.Unmarshal([]byte(metadataJSON), &t.Metadata); err != nil {
+		return t, fmt.Errorf("unmarshal metadata: %w", err)
+	}
 	return t, nil
 }
 
@@ -114,40 +166,155 @@ func (s *SQLiteTraceStore) List(ctx context.Context) ([]TraceInfo, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
 			   total_elapsed_ms, total_input_tokens, total_output_tokens,
-			   total_tool_calls, status, spans
-		FROM traces ORDER BY timestamp DESC`)
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM traces WHERE tenant_id = ? ORDER BY timestamp DESC`, TenantFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query traces: %w", err)
 	}
 	defer rows.Close()
+	return scanSQLiteTraces(rows)
+}
+
+func (s *SQLiteTraceStore) Query(ctx context.Context, q TraceQuery) (TracePage, error) {
+	where := "tenant_id = ?"
+	args := []any{TenantFromContext(ctx)}
+	if q.PipelineID != "" {
+		args = append(args, q.PipelineID)
+		where += " AND pipeline_id = ?"
+	}
+
+	var page TracePage
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM traces WHERE "+where, args...).Scan(&page.Total); err != nil {
+		return page, fmt.Errorf("count traces: %w", err)
+	}
+
+	var limit any = -1
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	args = append(args, limit, q.Offset)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+			   total_elapsed_ms, total_input_tokens, total_output_tokens,
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM traces WHERE `+where+` ORDER BY timestamp DESC LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return page, fmt.Errorf("query traces: %w", err)
+	}
+	defer rows.Close()
 
+	page.Traces, err = scanSQLiteTraces(rows)
+	return page, err
+}
+
+func scanSQLiteTraces(rows *sql.Rows) ([]TraceInfo, error) {
 	var traces []TraceInfo
 	for rows.Next() {
-		var t TraceInfo
-		var spansJSON string
-		if err := rows.Scan(
-			&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
-			&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
-			&t.TotalToolCalls, &t.Status, &spansJSON,
-		); err != nil {
-			return nil, fmt.Errorf("scan trace: %w", err)
-		}
-		if err := json.Unmarshal([]byte(spansJSON), &t.Spans); err != nil {
-			return nil, fmt.Errorf("unmarshal spans: %w", err)
+		t, err := scanSQLiteTrace(rows)
+		if err != nil {
+			return nil, err
 		}
 		traces = append(traces, t)
 	}
 	return traces, rows.Err()
 }
 
+func scanSQLiteTrace(rows *sql.Rows) (TraceInfo, error) {
+	var t TraceInfo
+	var spansJSON, metadataJSON string
+	if err := rows.Scan(
+		&t.TraceID, &t.PipelineID, &t.PipelineName, &t.Timestamp, &t.Input, &t.Output,
+		&t.TotalElapsedMs, &t.TotalInputTokens, &t.TotalOutputTokens,
+		&t.TotalToolCalls, &t.EstimatedCostUSD, &t.Status, &spansJSON, &metadataJSON,
+	); err != nil {
+		return t, fmt.Errorf("scan trace: %w", err)
+	}
+	if err := json.Unmarshal([]byte(spansJSON), &t.Spans); err != nil {
+		return t, fmt.Errorf("unmarshal spans: %w", err)
+	}
+	if err := json.Unmarshal([]byte(metadataJSON), &t.Metadata); err != nil {
+		return t, fmt.Errorf("unmarshal metadata: %w", err)
+	}
+	return t, nil
+}
+
+// Export streams the tenant's traces to w as rows are read, oldest first.
+func (s *SQLiteTraceStore) Export(ctx context.Context, w io.Writer, format string) error {
+	tw, err := newTraceWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT trace_id, pipeline_id, pipeline_name, timestamp, input, output,
+			   total_elapsed_ms, total_input_tokens, total_output_tokens,
+			   total_tool_calls, estimated_cost_usd, status, spans, metadata
+		FROM traces WHERE tenant_id = ? ORDER BY timestamp`, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("query traces: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanSQLiteTrace(rows)
+		if err != nil {
+			return err
+		}
+		if err := tw.Write(t); err != nil {
+			return fmt.Errorf("write trace: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Tenants lists every tenant with traces, in order.
+func (s *SQLiteTraceStore) Tenants(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT tenant_id FROM traces ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("query trace tenants: %w", err)
+	}
+	return scanTenants(rows)
+}
+
 func (s *SQLiteTraceStore) Delete(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM traces WHERE trace_id = ?`, id)
+	_, err := s.db.ExecContext(ctx, `DELETE FROM traces WHERE trace_id = ? AND tenant_id = ?`, id, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("delete trace: %w", err)
 	}
 	return nil
 }
 
+func (s *SQLiteTraceStore) Prune(ctx context.Context, policy RetentionPolicy) (int, error) {
+	var removed int64
+	if policy.MaxAgeDays > 0 {
+		res, err := s.db.ExecContext(ctx, `DELETE FROM traces WHERE timestamp < ?`, policy.cutoffMillis(time.Now()))
+		if err != nil {
+			return 0, fmt.Errorf("prune traces by age: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	if policy.MaxTraces > 0 {
+		res, err := s.db.ExecContext(ctx, `
+			DELETE FROM traces WHERE trace_id IN (
+				SELECT trace_id FROM (
+					SELECT trace_id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY timestamp DESC) AS row_num
+					FROM traces
+				) ranked WHERE row_num > ?
+			)`, policy.MaxTraces)
+		if err != nil {
+			return int(removed), fmt.Errorf("prune traces by count: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	return int(removed), nil
+}
+
 func (s *SQLiteTraceStore) Summary(ctx context.Context) (MetricsSummary, error) {
 	var m MetricsSummary
 	err := s.db.QueryRowContext(ctx, `
@@ -156,14 +323,27 @@ func (s *SQLiteTraceStore) Summary(ctx context.Context) (MetricsSummary, error)
 			COALESCE(SUM(total_input_tokens), 0),
 			COALESCE(SUM(total_output_tokens), 0),
 			COALESCE(SUM(total_tool_calls), 0),
-			COALESCE(AVG(total_elapsed_ms), 0)
-		FROM traces`).Scan(
+			COALESCE(AVG(total_elapsed_ms), 0),
+			COALESCE(SUM(estimated_cost_usd), 0)
+		FROM traces WHERE tenant_id = ?`, TenantFromContext(ctx)).Scan(
 		&m.TotalTraces, &m.TotalInputTokens, &m.TotalOutputTokens,
-		&m.TotalToolCalls, &m.AvgLatencyMs,
+		&m.TotalToolCalls, &m.AvgLatencyMs, &m.EstimatedCostUSD,
 	)
 	if err != nil {
 		return m, fmt.Errorf("query summary: %w", err)
 	}
+	m.TotalCostUSD = m.EstimatedCostUSD
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pipeline_id, COALESCE(SUM(estimated_cost_usd), 0)
+		FROM traces WHERE tenant_id = ?
+		GROUP BY pipeline_id`, TenantFromContext(ctx))
+	if err != nil {
+		return m, fmt.Errorf("query cost by pipeline: %w", err)
+	}
+	if m.CostByPipeline, err = scanCostByPipeline(rows); err != nil {
+		return m, err
+	}
 	return m, nil
 }
 
@@ -187,25 +367,38 @@ func (s *SQLitePipelineStore) Save(ctx context.Context, p PipelineInfo) error {
 		return fmt.Errorf("marshal layout: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, `
-		INSERT OR REPLACE INTO pipelines (id, name, description, nodes, edges, layout)
-		VALUES (?, ?, ?, ?, ?, ?)`,
-		p.ID, p.Name, p.Description, string(nodes), string(edges), string(layout),
+	tenantID := TenantFromContext(ctx)
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO pipelines (id, version, tenant_id, name, description, nodes, edges, layout, saved_at)
+		SELECT ?, (SELECT COALESCE(MAX(version), 0) + 1 FROM pipelines WHERE id = ?), ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM pipelines WHERE id = ? AND tenant_id <> ?)`,
+		p.ID, p.ID, tenantID, p.Name, p.Description, string(nodes), string(edges), string(layout), time.Now().UnixMilli(),
+		p.ID, tenantID,
 	)
 	if err != nil {
 		return fmt.Errorf("insert pipeline: %w", err)
 	}
-	return nil
+	return checkSaved(res)
 }
 
 func (s *SQLitePipelineStore) Get(ctx context.Context, id string) (PipelineInfo, error) {
+	return s.get(ctx, `
+		SELECT id, version, name, description, nodes, edges, layout
+		FROM pipelines WHERE id = ? AND tenant_id = ? ORDER BY version DESC LIMIT 1`, id, TenantFromContext(ctx))
+}
+
+func (s *SQLitePipelineStore) GetVersion(ctx context.Context, id string, version int) (PipelineInfo, error) {
+	return s.get(ctx, `
+		SELECT id, version, name, description, nodes, edges, layout
+		FROM pipelines WHERE id = ? AND tenant_id = ? AND version = ?`, id, TenantFromContext(ctx), version)
+}
+
+func (s *SQLitePipelineStore) get(ctx context.Context, query string, args ...any) (PipelineInfo, error) {
 	var p PipelineInfo
 	var nodesJSON, edgesJSON, layoutJSON string
 
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, name, description, nodes, edges, layout
-		FROM pipelines WHERE id = ?`, id).Scan(
-		&p.ID, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON,
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
+		&p.ID, &p.Version, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON,
 	)
 	if err == sql.ErrNoRows {
 		return p, ErrNotFound
@@ -228,8 +421,10 @@ func (s *SQLitePipelineStore) Get(ctx context.Context, id string) (PipelineInfo,
 
 func (s *SQLitePipelineStore) List(ctx context.Context) ([]PipelineInfo, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, name, description, nodes, edges, layout
-		FROM pipelines ORDER BY name`)
+		SELECT id, version, name, description, nodes, edges, layout
+		FROM pipelines p WHERE tenant_id = ?
+			AND version = (SELECT MAX(version) FROM pipelines WHERE id = p.id)
+		ORDER BY name`, TenantFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query pipelines: %w", err)
 	}
@@ -239,7 +434,7 @@ func (s *SQLitePipelineStore) List(ctx context.Context) ([]PipelineInfo, error)
 	for rows.Next() {
 		var p PipelineInfo
 		var nodesJSON, edgesJSON, layoutJSON string
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON); err != nil {
+		if err := rows.Scan(&p.ID, &p.Version, &p.Name, &p.Description, &nodesJSON, &edgesJSON, &layoutJSON); err != nil {
 			return nil, fmt.Errorf("scan pipeline: %w", err)
 		}
 		if err := json.Unmarshal([]byte(nodesJSON), &p.Nodes); err != nil {
@@ -256,8 +451,27 @@ func (s *SQLitePipelineStore) List(ctx context.Context) ([]PipelineInfo, error)
 	return pipelines, rows.Err()
 }
 
+func (s *SQLitePipelineStore) ListVersions(ctx context.Context, id string) ([]PipelineVersion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, version, name, saved_at
+		FROM pipelines WHERE id = ? AND tenant_id = ? ORDER BY version`, id, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline versions: %w", err)
+	}
+	return scanPipelineVersions(rows)
+}
+
+// Tenants lists every tenant with pipelines, in order.
+func (s *SQLitePipelineStore) Tenants(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT tenant_id FROM pipelines ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline tenants: %w", err)
+	}
+	return scanTenants(rows)
+}
+
 func (s *SQLitePipelineStore) Delete(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM pipelines WHERE id = ?`, id)
+	_, err := s.db.ExecContext(ctx, `DELETE FROM pipelines WHERE id = ? AND tenant_id = ?`, id, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("delete pipeline: %w", err)
 	}
@@ -267,3 +481,71 @@ func (s *SQLitePipelineStore) Delete(ctx context.Context, id string) error {
 func (s *SQLitePipelineStore) Close() error {
 	return s.db.Close()
 }
+
+// SessionStore implementation
+
+func (s *SQLiteSessionStore) Get(ctx context.Context, id string) (Session, error) {
+	sess := Session{ID: id}
+	var messagesJSON string
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT messages, updated_at FROM sessions
+		WHERE session_id = ? AND tenant_id = ?`, id, TenantFromContext(ctx)).Scan(&messagesJSON, &sess.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return sess, ErrNotFound
+	}
+	if err != nil {
+		return sess, fmt.Errorf("query session: %w", err)
+	}
+	if expired(sess.UpdatedAt, s.ttl, time.Now()) {
+		return sess, ErrNotFound
+	}
+
+	if err := json.Unmarshal([]byte(messagesJSON), &sess.Messages); err != nil {
+		return sess, fmt.Errorf("unmarshal messages: %w", err)
+	}
+	return sess, nil
+}
+
+// Set upserts the session and drops sessions that have outlived the TTL.
+func (s *SQLiteSessionStore) Set(ctx context.Context, sess Session) error {
+	if sess.Messages == nil {
+		sess.Messages = []SessionMessage{}
+	}
+	messages, err := json.Marshal(sess.Messages)
+	if err != nil {
+		return fmt.Errorf("marshal messages: %w", err)
+	}
+
+	now := time.Now()
+	_, err = s.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO sessions (tenant_id, session_id, messages, updated_at)
+		VALUES (?, ?, ?, ?)`,
+		TenantFromContext(ctx), sess.ID, string(messages), now.UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("upsert session: %w", err)
+	}
+
+	if s.ttl > 0 {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE updated_at < ?`, now.Add(-s.ttl).UnixMilli()); err != nil {
+			return fmt.Errorf("delete expired sessions: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteSessionStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE session_id = ? AND tenant_id = ?`, id, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteSessionStore) Close() error {
+	return s.db.Close()
+}
@@ -2,27 +2,49 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"time"
 )
 
 // ErrNotFound is returned when an entity is not found
 var ErrNotFound = errors.New("not found")
 
+// ErrConflict is returned when an entity ID is owned by another tenant
+var ErrConflict = errors.New("conflict")
+
+type tenantKey struct{}
+
+// WithTenant scopes store operations made with ctx to the given tenant.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or "" if none.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
 // TraceInfo represents a recorded trace
 type TraceInfo struct {
-	TraceID           string     `json:"trace_id"`
-	PipelineID        string     `json:"pipeline_id"`
-	PipelineName      string     `json:"pipeline_name"`
-	Timestamp         int64      `json:"timestamp"`
-	Input             string     `json:"input"`
-	Output            string     `json:"output"`
-	TotalElapsedMs    int64      `json:"total_elapsed_ms"`
-	TotalInputTokens  int        `json:"total_input_tokens"`
-	TotalOutputTokens int        `json:"total_output_tokens"`
-	TotalToolCalls    int        `json:"total_tool_calls"`
-	Status            string     `json:"status"`
-	Spans             []SpanInfo `json:"spans,omitempty"`
+	TraceID           string         `json:"trace_id"`
+	PipelineID        string         `json:"pipeline_id"`
+	PipelineName      string         `json:"pipeline_name"`
+	Timestamp         int64          `json:"timestamp"`
+	Input             string         `json:"input"`
+	Output            string         `json:"output"`
+	TotalElapsedMs    int64          `json:"total_elapsed_ms"`
+	TotalInputTokens  int            `json:"total_input_tokens"`
+	TotalOutputTokens int            `json:"total_output_tokens"`
+	TotalToolCalls    int            `json:"total_tool_calls"`
+	EstimatedCostUSD  float64        `json:"estimated_cost_usd"`
+	Status            string         `json:"status"`
+	Spans             []SpanInfo     `json:"spans,omitempty"`
+	Metadata          map[string]any `json:"metadata,omitempty"`
 }
 
 // SpanInfo represents a span within a trace
@@ -39,33 +61,84 @@ type SpanInfo struct {
 	OutputTokens   int    `json:"output_tokens"`
 	ToolCallCount  int    `json:"tool_call_count"`
 	IterationCount int    `json:"iteration_count"`
+
+	Metadata map[string]any `json:"metadata,omitempty"` // e.g. "timed_out": true
 }
 
 // MetricsSummary contains aggregated metrics
 type MetricsSummary struct {
-	TotalTraces       int     `json:"total_traces"`
-	TotalInputTokens  int     `json:"total_input_tokens"`
-	TotalOutputTokens int     `json:"total_output_tokens"`
-	TotalToolCalls    int     `json:"total_tool_calls"`
-	AvgLatencyMs      float64 `json:"avg_latency_ms"`
+	TotalTraces       int                `json:"total_traces"`
+	TotalInputTokens  int                `json:"total_input_tokens"`
+	TotalOutputTokens int                `json:"total_output_tokens"`
+	TotalToolCalls    int                `json:"total_tool_calls"`
+	AvgLatencyMs      float64            `json:"avg_latency_ms"`
+	EstimatedCostUSD  float64            `json:"estimated_cost_usd"`
+	TotalCostUSD      float64            `json:"total_cost_usd"`
+	CostByPipeline    map[string]float64 `json:"cost_by_pipeline"`
+	Truncated         bool               `json:"truncated,omitempty"` // Only the newest ListLimit traces were aggregated
+}
+
+// RetentionPolicy bounds trace storage. Zero fields are not enforced.
+type RetentionPolicy struct {
+	MaxTraces  int // Keep at most this many of the newest traces of each tenant
+	MaxAgeDays int // Delete traces older than this
+}
+
+// cutoffMillis returns the oldest timestamp MaxAgeDays allows.
+func (p RetentionPolicy) cutoffMillis(now time.Time) int64 {
+	return now.AddDate(0, 0, -p.MaxAgeDays).UnixMilli()
+}
+
+// TraceQuery filters and paginates trace listings
+type TraceQuery struct {
+	PipelineID string // Optional: restrict to one pipeline
+	Limit      int    // Optional: max traces to return (0 = no limit)
+	Offset     int
+}
+
+// TracePage is one page of a trace query
+type TracePage struct {
+	Traces []TraceInfo `json:"traces"`
+	Total  int         `json:"total"`
 }
 
 // NodeInfo represents a node in a pipeline
 type NodeInfo struct {
-	ID       string   `json:"id"`
-	NodeType string   `json:"node_type"`
-	Model    *string  `json:"model,omitempty"`
-	Prompt   *string  `json:"prompt,omitempty"`
-	Tools    []string `json:"tools,omitempty"`
-	X        *float64 `json:"x,omitempty"`
-	Y        *float64 `json:"y,omitempty"`
+	ID           string   `json:"id"`
+	NodeType     string   `json:"node_type"`
+	Model        *string  `json:"model,omitempty"`
+	Prompt       *string  `json:"prompt,omitempty"`
+	SystemPrompt *string  `json:"system_prompt,omitempty"`
+	Tools        []string `json:"tools,omitempty"`
+	X            *float64 `json:"x,omitempty"`
+	Y            *float64 `json:"y,omitempty"`
+
+	TargetNodes     []string `json:"target_nodes,omitempty"`
+	TimeoutSecs     int      `json:"timeout_secs,omitempty"`
+	ConditionNode   string   `json:"condition_node,omitempty"`
+	MaxIter         int      `json:"max_iter,omitempty"`
+	ToolConcurrency int      `json:"tool_concurrency,omitempty"`
+
+	ResponseSchema json.RawMessage `json:"response_schema,omitempty"`
+	InputTemplate  string          `json:"input_template,omitempty"`
+
+	MaxInputTokens     int    `json:"max_input_tokens,omitempty"`
+	TruncationStrategy string `json:"truncation_strategy,omitempty"`
+
+	OutputParser    json.RawMessage `json:"output_parser,omitempty"` // {"type": ..., "pattern": ...}
+	OutputTransform string          `json:"output_transform,omitempty"`
+	JoinStrategy    string          `json:"join_strategy,omitempty"`
+
+	ChunkSize int    `json:"chunk_size,omitempty"`
+	Delimiter string `json:"delimiter,omitempty"`
 }
 
 // EdgeInfo represents an edge in a pipeline
 type EdgeInfo struct {
-	From     json.RawMessage `json:"from"`
-	To       json.RawMessage `json:"to"`
-	EdgeType *string         `json:"edge_type,omitempty"`
+	From      json.RawMessage `json:"from"`
+	To        json.RawMessage `json:"to"`
+	EdgeType  *string         `json:"edge_type,omitempty"`
+	Condition string          `json:"condition,omitempty"`
 }
 
 // Position represents a 2D position
@@ -82,23 +155,123 @@ type PipelineInfo struct {
 	Nodes       []NodeInfo          `json:"nodes"`
 	Edges       []EdgeInfo          `json:"edges"`
 	Layout      map[string]Position `json:"layout,omitempty"`
+	Version     int                 `json:"version,omitempty"` // set by the store; each Save adds a version
+}
+
+// PipelineVersion describes one saved version of a pipeline
+type PipelineVersion struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	SavedAt int64  `json:"saved_at"` // unix millis; 0 for versions saved before versioning
+}
+
+// checkSaved reports ErrConflict when an upsert matched a row owned by another tenant.
+func checkSaved(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrConflict
+	}
+	return nil
 }
 
 // TraceStore defines the interface for trace persistence
 type TraceStore interface {
 	Add(ctx context.Context, t TraceInfo) error
 	Get(ctx context.Context, id string) (TraceInfo, error)
+	// List, Summary and Export cover every trace of the tenant, or only the
+	// newest ListLimit of them for stores implementing ListLimiter. Query's
+	// Total always counts every matching trace.
 	List(ctx context.Context) ([]TraceInfo, error)
+	Query(ctx context.Context, q TraceQuery) (TracePage, error)
 	Delete(ctx context.Context, id string) error
+	// Prune deletes traces outside policy for every tenant, applying MaxTraces
+	// to each tenant separately, and returns how many were removed
+	Prune(ctx context.Context, policy RetentionPolicy) (int, error)
 	Summary(ctx context.Context) (MetricsSummary, error)
+	Export(ctx context.Context, w io.Writer, format string) error
 	Close() error
 }
 
-// PipelineStore defines the interface for pipeline persistence
+// PipelineStore defines the interface for pipeline persistence. Save adds a
+// new version rather than overwriting; Get and List return latest versions and
+// Delete removes every version.
 type PipelineStore interface {
 	Save(ctx context.Context, p PipelineInfo) error
 	Get(ctx context.Context, id string) (PipelineInfo, error)
+	GetVersion(ctx context.Context, id string, version int) (PipelineInfo, error)
 	List(ctx context.Context) ([]PipelineInfo, error)
+	ListVersions(ctx context.Context, id string) ([]PipelineVersion, error)
 	Delete(ctx context.Context, id string) error
 	Close() error
 }
+
+// ListLimiter is implemented by trace stores whose List, Summary and Export
+// read at most ListLimit of a tenant's newest traces.
+type ListLimiter interface {
+	ListLimit() int
+}
+
+// HealthChecker is implemented by stores that can verify their database connection
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// TenantLister is implemented by stores that can enumerate the tenants
+// holding records, which Backup needs to cover every tenant.
+type TenantLister interface {
+	Tenants(ctx context.Context) ([]string, error)
+}
+
+// scanTenants collects single-column tenant_id rows.
+func scanTenants(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var tenants []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenantID)
+	}
+	return tenants, rows.Err()
+}
+
+// scanPipelineVersions collects (id, version, name, saved_at) rows, reporting
+// ErrNotFound when there are none.
+func scanPipelineVersions(rows *sql.Rows) ([]PipelineVersion, error) {
+	defer rows.Close()
+	var versions []PipelineVersion
+	for rows.Next() {
+		var v PipelineVersion
+		if err := rows.Scan(&v.ID, &v.Version, &v.Name, &v.SavedAt); err != nil {
+			return nil, fmt.Errorf("scan pipeline version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	return versions, nil
+}
+
+// scanCostByPipeline collects (pipeline_id, cost) rows into a map
+func scanCostByPipeline(rows *sql.Rows) (map[string]float64, error) {
+	defer rows.Close()
+	costs := make(map[string]float64)
+	for rows.Next() {
+		var id string
+		var cost float64
+		if err := rows.Scan(&id, &cost); err != nil {
+			return nil, fmt.Errorf("scan pipeline cost: %w", err)
+		}
+		costs[id] = cost
+	}
+	return costs, rows.Err()
+}
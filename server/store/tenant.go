@@ -0,0 +1,44 @@
+package store
+
+import "context"
+
+// tenantPipelineStore pins every operation on a shared PipelineStore to one tenant.
+type tenantPipelineStore struct {
+	inner    PipelineStore
+	tenantID string
+}
+
+// NewTenantPipelineStore returns a view of inner that always operates as
+// tenantID, regardless of the tenant carried by the caller's context.
+// Closing the view does not close inner.
+func NewTenantPipelineStore(inner PipelineStore, tenantID string) PipelineStore {
+	return &tenantPipelineStore{inner: inner, tenantID: tenantID}
+}
+
+func (s *tenantPipelineStore) Save(ctx context.Context, p PipelineInfo) error {
+	return s.inner.Save(WithTenant(ctx, s.tenantID), p)
+}
+
+func (s *tenantPipelineStore) Get(ctx context.Context, id string) (PipelineInfo, error) {
+	return s.inner.Get(WithTenant(ctx, s.tenantID), id)
+}
+
+func (s *tenantPipelineStore) GetVersion(ctx context.Context, id string, version int) (PipelineInfo, error) {
+	return s.inner.GetVersion(WithTenant(ctx, s.tenantID), id, version)
+}
+
+func (s *tenantPipelineStore) List(ctx context.Context) ([]PipelineInfo, error) {
+	return s.inner.List(WithTenant(ctx, s.tenantID))
+}
+
+func (s *tenantPipelineStore) ListVersions(ctx context.Context, id string) ([]PipelineVersion, error) {
+	return s.inner.ListVersions(WithTenant(ctx, s.tenantID), id)
+}
+
+func (s *tenantPipelineStore) Delete(ctx context.Context, id string) error {
+	return s.inner.Delete(WithTenant(ctx, s.tenantID), id)
+}
+
+func (s *tenantPipelineStore) Close() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hubenschmidt/go-fissio/config"
+)
+
+// templateList returns the current templates. Reloads swap in a new slice,
+// so callers may keep using the one they got.
+func (s *Server) templateList() []PipelineInfo {
+	s.templatesMu.RLock()
+	defer s.templatesMu.RUnlock()
+	return s.templates
+}
+
+// Reload re-reads Config.PipelineDir, replacing the templates with the
+// configured ones plus every *.json, *.yaml and *.yml pipeline in it; a file's
+// pipeline replaces a configured template with the same ID. On error the
+// current templates are kept. Without a PipelineDir it does nothing.
+func (s *Server) Reload() error {
+	if s.pipelineDir == "" {
+		return nil
+	}
+	loaded, err := loadTemplateDir(s.pipelineDir)
+	if err != nil {
+		return err
+	}
+
+	templates := make([]PipelineInfo, 0, len(s.baseTemplates)+len(loaded))
+	for _, t := range s.baseTemplates {
+		if !containsPipeline(loaded, t.ID) {
+			templates = append(templates, t)
+		}
+	}
+	templates = append(templates, loaded...)
+
+	s.templatesMu.Lock()
+	s.templates = templates
+	s.templatesMu.Unlock()
+
+	s.logger.Info("pipeline templates loaded", slog.String("dir", s.pipelineDir), slog.Int("count", len(loaded)))
+	return nil
+}
+
+// loadTemplateDir reads the pipelines in dir in file name order. Pipelines
+// without an ID take the file name without its extension.
+func loadTemplateDir(dir string) ([]PipelineInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read pipeline dir: %w", err)
+	}
+
+	var templates []PipelineInfo
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		var cfg *config.PipelineConfig
+		if ext == ".json" {
+			cfg, err = config.LoadPipeline(path)
+		} else {
+			cfg, err = config.LoadPipelineYAML(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", path, err)
+		}
+
+		if cfg.ID == "" {
+			cfg.ID = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if err := checkSavable(cfg); err != nil {
+			return nil, fmt.Errorf("load %s: %w", path, err)
+		}
+		templates = append(templates, infoFromConfig(cfg))
+	}
+	return templates, nil
+}
+
+func containsPipeline(pipelines []PipelineInfo, id string) bool {
+	for _, p := range pipelines {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/hubenschmidt/go-fissio/server/store"
+	"github.com/hubenschmidt/go-fissio/tools"
+)
+
+// registryFor returns the tool registry for a request: the authenticated
+// tenant's isolated registry if one was created through /api/admin/tenants,
+// otherwise the shared registry scoped to the toolTenant's allowed tools.
+// Without TenantTools configured every other request gets the full registry.
+func (s *Server) registryFor(r *http.Request) *tools.Registry {
+	s.tenantsMu.RLock()
+	registry, ok := s.tenantRegistries[store.TenantFromContext(r.Context())]
+	s.tenantsMu.RUnlock()
+	if ok {
+		return registry
+	}
+
+	if len(s.tenantTools) == 0 {
+		return s.registry
+	}
+	return tools.ScopedRegistry(s.registry, s.tenantTools[toolTenant(r)])
+}
+
+// pipelinesFor returns the tenant's isolated pipeline store, or the shared
+// store when the tenant has none.
+func (s *Server) pipelinesFor(tenantID string) store.PipelineStore {
+	s.tenantsMu.RLock()
+	defer s.tenantsMu.RUnlock()
+	if ps, ok := s.tenantPipelines[tenantID]; ok {
+		return ps
+	}
+	return s.pipelines
+}
+
+func (s *Server) handleTenantCreate(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r.Context()) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ID == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	// Seed from the global registry; later registrations on either side stay separate
+	registry := s.registry.Clone()
+	if len(req.Tools) > 0 {
+		registry = tools.ScopedRegistry(s.registry, req.Tools)
+	}
+
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	if _, ok := s.tenantRegistries[req.ID]; ok {
+		http.Error(w, "tenant already exists", http.StatusConflict)
+		return
+	}
+
+	pipelines := store.NewTenantPipelineStore(s.pipelines, req.ID)
+	if s.newTenantPipelines != nil {
+		ps, err := s.newTenantPipelines(req.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pipelines = ps
+	}
+
+	s.tenantRegistries[req.ID] = registry
+	s.tenantPipelines[req.ID] = pipelines
+
+	names := registry.List()
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(TenantInfo{ID: req.ID, Tools: names})
+}
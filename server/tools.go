@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hubenschmidt/go-fissio/server/store"
+	"github.com/hubenschmidt/go-fissio/tools"
+)
+
+// disabledTool remembers an unregistered tool and the registries it was
+// removed from, so re-enabling restores it exactly where it was.
+type disabledTool struct {
+	tool       tools.Tool
+	registries []*tools.Registry
+}
+
+// disableTool unregisters name from the shared registry and every isolated
+// tenant registry. It reports false if the tool is unknown; disabling a
+// disabled tool is a no-op.
+func (s *Server) disableTool(name string) (tools.Tool, bool) {
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	disabled, ok := s.disabledTools[name]
+	for _, registry := range s.allRegistries() {
+		t, found := registry.Get(name)
+		if !found {
+			continue
+		}
+		registry.Unregister(name)
+		disabled.tool = t
+		disabled.registries = append(disabled.registries, registry)
+		ok = true
+	}
+	if !ok {
+		return nil, false
+	}
+	s.disabledTools[name] = disabled
+	return disabled.tool, true
+}
+
+// enableTool swaps a tool removed by disableTool back into the registries it
+// was removed from. It reports false if the tool is unknown; enabling an
+// enabled tool is a no-op.
+func (s *Server) enableTool(name string) (tools.Tool, bool) {
+	s.tenantsMu.Lock()
+	defer s.tenantsMu.Unlock()
+
+	disabled, ok := s.disabledTools[name]
+	if !ok {
+		return s.registry.Get(name)
+	}
+	for _, registry := range disabled.registries {
+		registry.Replace(disabled.tool)
+	}
+	delete(s.disabledTools, name)
+	return disabled.tool, true
+}
+
+// allRegistries returns the shared registry followed by the isolated tenant
+// registries. Callers must hold tenantsMu.
+func (s *Server) allRegistries() []*tools.Registry {
+	registries := []*tools.Registry{s.registry}
+	for _, r := range s.tenantRegistries {
+		registries = append(registries, r)
+	}
+	return registries
+}
+
+// disabledToolsFor lists the disabled tools registryFor(r) would otherwise include.
+func (s *Server) disabledToolsFor(r *http.Request) []tools.Tool {
+	s.tenantsMu.RLock()
+	defer s.tenantsMu.RUnlock()
+
+	tenantID := toolTenant(r)
+	isolated, hasIsolated := s.tenantRegistries[store.TenantFromContext(r.Context())]
+	var result []tools.Tool
+	for _, d := range s.disabledTools {
+		for _, registry := range d.registries {
+			if registry == isolated || (!hasIsolated && registry == s.registry && s.tenantAllows(tenantID, d.tool.Name())) {
+				result = append(result, d.tool)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// tenantAllows reports whether TenantTools lets a tenant on the shared registry use name.
+func (s *Server) tenantAllows(tenantID, name string) bool {
+	if len(s.tenantTools) == 0 {
+		return true
+	}
+	for _, allowed := range s.tenantTools[tenantID] {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// handleToolDelete disables a tool for every tenant until it is re-enabled
+// with PUT /api/tools.
+func (s *Server) handleToolDelete(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r.Context()) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if _, ok := s.disableTool(r.PathValue("name")); !ok {
+		http.Error(w, "tool not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// handleToolReplace sets a tool's enabled state, swapping a disabled tool's
+// implementation back into the registries it was removed from.
+func (s *Server) handleToolReplace(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r.Context()) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req UpdateToolRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var t tools.Tool
+	var ok bool
+	if req.Enabled {
+		t, ok = s.enableTool(req.Name)
+	} else {
+		t, ok = s.disableTool(req.Name)
+	}
+	if !ok {
+		http.Error(w, "tool not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ToolInfo{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters:  t.Parameters(),
+		Enabled:     req.Enabled,
+	})
+}
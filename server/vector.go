@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hubenschmidt/go-fissio/llm"
+	"github.com/hubenschmidt/go-fissio/vector"
+)
+
+// handleVectorIndexBatch embeds and upserts documents one at a time, streaming
+// a progress event per indexed document. A failed document emits an error
+// event and the batch continues; documents without an ID or content are skipped.
+func (s *Server) handleVectorIndexBatch(w http.ResponseWriter, r *http.Request) {
+	embedder, ok := s.client.(llm.EmbeddingClient)
+	if !ok || s.vectorStore == nil {
+		http.Error(w, "vector indexing is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req IndexBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	total := len(req.Documents)
+	var indexed, skipped, failed int
+	for _, doc := range req.Documents {
+		if ctx.Err() != nil {
+			return
+		}
+		if doc.ID == "" || doc.Content == "" {
+			skipped++
+			continue
+		}
+
+		resp, err := embedder.Embed(ctx, s.embedModel, doc.Content)
+		if err == nil {
+			err = s.vectorStore.Upsert(ctx, []vector.Document{{
+				ID:        doc.ID,
+				Content:   doc.Content,
+				Embedding: resp.Embedding,
+				Metadata:  doc.Metadata,
+			}})
+		}
+		if err != nil {
+			failed++
+			writeSSE(w, flusher, "error", map[string]any{"doc_id": doc.ID, "error": err.Error()})
+			continue
+		}
+
+		indexed++
+		writeSSE(w, flusher, "progress", map[string]any{"indexed": indexed, "total": total, "doc_id": doc.ID})
+	}
+
+	writeSSE(w, flusher, "done", map[string]any{"indexed": indexed, "skipped": skipped, "errors": failed})
+}
@@ -0,0 +1,361 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CalculatorTool evaluates arithmetic expressions deterministically so agents
+// don't have to do math in their heads.
+type CalculatorTool struct{}
+
+func NewCalculatorTool() *CalculatorTool {
+	return &CalculatorTool{}
+}
+
+func (c *CalculatorTool) Name() string {
+	return "calculate_expression"
+}
+
+func (c *CalculatorTool) Description() string {
+	return "Evaluates an arithmetic expression exactly. Supports + - * / % ^, parentheses, " +
+		"the constants pi and e, and math functions such as sqrt, abs, log, exp, sin, cos, min and max."
+}
+
+func (c *CalculatorTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"expression": {
+				"type": "string",
+				"description": "The expression to evaluate, e.g. 2*(3+4) or sqrt(2)^2"
+			}
+		},
+		"required": ["expression"]
+	}`)
+}
+
+func (c *CalculatorTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var params struct {
+		Expression string `json:"expression"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	result, err := EvalExpression(params.Expression)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(map[string]any{"result": result, "expression": params.Expression})
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(out), nil
+}
+
+// ErrDivisionByZero is returned by EvalExpression for x/0 and x%0.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// EvalExpression parses and evaluates an arithmetic expression. Operator precedence,
+// from lowest: + -, then * / %, then unary + -, then ^ (right-associative).
+func EvalExpression(expr string) (float64, error) {
+	p := &exprParser{input: expr}
+	p.next()
+
+	v, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	if p.tok.kind != tokEOF {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.tok.text, p.tok.pos)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("result is not a finite number")
+	}
+	return v, nil
+}
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokInvalid
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// exprParser is a recursive descent parser that evaluates as it parses.
+type exprParser struct {
+	input string
+	pos   int
+	tok   exprToken
+}
+
+func (p *exprParser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = exprToken{kind: tokEOF, pos: start}
+		return
+	}
+
+	ch := p.input[p.pos]
+	switch {
+	case ch >= '0' && ch <= '9' || ch == '.':
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		// Exponent, e.g. 1.5e-3
+		if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+			end := p.pos + 1
+			if end < len(p.input) && (p.input[end] == '+' || p.input[end] == '-') {
+				end++
+			}
+			if end < len(p.input) && isDigit(p.input[end]) {
+				for end < len(p.input) && isDigit(p.input[end]) {
+					end++
+				}
+				p.pos = end
+			}
+		}
+		text := p.input[start:p.pos]
+		num, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			p.tok = exprToken{kind: tokInvalid, text: text, pos: start}
+			return
+		}
+		p.tok = exprToken{kind: tokNumber, text: text, num: num, pos: start}
+	case unicode.IsLetter(rune(ch)):
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		p.tok = exprToken{kind: tokIdent, text: strings.ToLower(p.input[start:p.pos]), pos: start}
+	case strings.IndexByte("+-*/%^(),", ch) >= 0:
+		p.pos++
+		p.tok = exprToken{kind: tokOp, text: string(ch), pos: start}
+	default:
+		p.pos++
+		p.tok = exprToken{kind: tokInvalid, text: string(ch), pos: start}
+	}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func (p *exprParser) isOp(ops string) bool {
+	return p.tok.kind == tokOp && strings.Contains(ops, p.tok.text)
+}
+
+// parseExpr: term (('+' | '-') term)*
+func (p *exprParser) parseExpr() (float64, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for p.isOp("+-") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+	return left, nil
+}
+
+// parseTerm: unary (('*' | '/' | '%') unary)*
+func (p *exprParser) parseTerm() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for p.isOp("*/%") {
+		op := p.tok.text
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "*":
+			left *= right
+		case "/":
+			if right == 0 {
+				return 0, ErrDivisionByZero
+			}
+			left /= right
+		case "%":
+			if right == 0 {
+				return 0, ErrDivisionByZero
+			}
+			left = math.Mod(left, right)
+		}
+	}
+	return left, nil
+}
+
+// parseUnary: ('+' | '-') unary | power
+func (p *exprParser) parseUnary() (float64, error) {
+	if p.isOp("+-") {
+		op := p.tok.text
+		p.next()
+		v, err := p.parseUnary()
+		if op == "-" {
+			v = -v
+		}
+		return v, err
+	}
+	return p.parsePower()
+}
+
+// parsePower: primary ('^' unary)?, so 2^3^2 = 2^9 and -2^2 = -4.
+func (p *exprParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if !p.isOp("^") {
+		return base, nil
+	}
+	p.next()
+	exp, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exp), nil
+}
+
+// parsePrimary: number | constant | function '(' args ')' | '(' expr ')'
+func (p *exprParser) parsePrimary() (float64, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		p.next()
+		return tok.num, nil
+	case tok.kind == tokIdent:
+		p.next()
+		if !p.isOp("(") {
+			if v, ok := calcConstants[tok.text]; ok {
+				return v, nil
+			}
+			return 0, fmt.Errorf("unknown identifier %q", tok.text)
+		}
+		return p.parseCall(tok.text)
+	case p.isOp("("):
+		p.next()
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		if !p.isOp(")") {
+			return 0, fmt.Errorf("missing closing parenthesis at position %d", p.tok.pos)
+		}
+		p.next()
+		return v, nil
+	case tok.kind == tokEOF:
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+	return 0, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseCall(name string) (float64, error) {
+	fn, ok := calcFunctions[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", name)
+	}
+
+	p.next() // consume '('
+	var args []float64
+	for !p.isOp(")") {
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, v)
+		if p.isOp(",") {
+			p.next()
+			continue
+		}
+		if !p.isOp(")") {
+			return 0, fmt.Errorf("expected ',' or ')' at position %d", p.tok.pos)
+		}
+	}
+	p.next() // consume ')'
+
+	if len(args) != fn.arity {
+		return 0, fmt.Errorf("%s takes %d argument(s), got %d", name, fn.arity, len(args))
+	}
+	return fn.call(args), nil
+}
+
+var calcConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+type calcFunction struct {
+	arity int
+	call  func(args []float64) float64
+}
+
+func calcUnary(f func(float64) float64) calcFunction {
+	return calcFunction{1, func(a []float64) float64 { return f(a[0]) }}
+}
+
+func calcBinary(f func(float64, float64) float64) calcFunction {
+	return calcFunction{2, func(a []float64) float64 { return f(a[0], a[1]) }}
+}
+
+var calcFunctions = map[string]calcFunction{
+	"abs":   calcUnary(math.Abs),
+	"sqrt":  calcUnary(math.Sqrt),
+	"cbrt":  calcUnary(math.Cbrt),
+	"exp":   calcUnary(math.Exp),
+	"log":   calcUnary(math.Log),
+	"ln":    calcUnary(math.Log),
+	"log2":  calcUnary(math.Log2),
+	"log10": calcUnary(math.Log10),
+	"sin":   calcUnary(math.Sin),
+	"cos":   calcUnary(math.Cos),
+	"tan":   calcUnary(math.Tan),
+	"asin":  calcUnary(math.Asin),
+	"acos":  calcUnary(math.Acos),
+	"atan":  calcUnary(math.Atan),
+	"floor": calcUnary(math.Floor),
+	"ceil":  calcUnary(math.Ceil),
+	"round": calcUnary(math.Round),
+	"trunc": calcUnary(math.Trunc),
+	"min":   calcBinary(math.Min),
+	"max":   calcBinary(math.Max),
+	"pow":   calcBinary(math.Pow),
+	"atan2": calcBinary(math.Atan2),
+	"hypot": calcBinary(math.Hypot),
+}
+
+func init() {
+	Register(NewCalculatorTool())
+}
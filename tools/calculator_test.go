@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEvalExpression(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want float64
+	}{
+		{"addition", "1 + 2", 3},
+		{"multiplication before addition", "2 + 3 * 4", 14},
+		{"left-associative subtraction", "10 - 4 - 3", 3},
+		{"left-associative division", "100 / 10 / 5", 2},
+		{"modulo", "7 % 3", 1},
+		{"power before multiplication", "2 * 3 ^ 2", 18},
+		{"right-associative power", "2 ^ 3 ^ 2", 512},
+		{"parentheses", "(2 + 3) * 4", 20},
+		{"nested parentheses", "((1 + 2) * (3 + 4)) / 7", 3},
+		{"unary minus", "-5 + 2", -3},
+		{"double unary minus", "--5", 5},
+		{"unary minus binds looser than power", "-2 ^ 2", -4},
+		{"unary minus in exponent", "2 ^ -1", 0.5},
+		{"unary minus on parentheses", "-(3 - 5)", 2},
+		{"scientific notation", "1.5e3 + 1", 1501},
+		{"constant", "2 * pi", 2 * math.Pi},
+		{"function", "sqrt(16) + abs(-2)", 6},
+		{"binary function", "max(3, min(10, 7))", 7},
+		{"whitespace", "  3\t*\n2 ", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvalExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("EvalExpression(%q) error: %v", tt.expr, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EvalExpression(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr error // nil: any error
+	}{
+		{"division by zero", "1 / 0", ErrDivisionByZero},
+		{"division by zero expression", "4 / (2 - 2)", ErrDivisionByZero},
+		{"modulo by zero", "5 % 0", ErrDivisionByZero},
+		{"empty", "", nil},
+		{"trailing operator", "1 +", nil},
+		{"leading operator", "* 2", nil},
+		{"missing closing parenthesis", "(1 + 2", nil},
+		{"unmatched closing parenthesis", "1 + 2)", nil},
+		{"empty parentheses", "()", nil},
+		{"adjacent numbers", "1 2", nil},
+		{"invalid character", "2 & 3", nil},
+		{"malformed number", "1.2.3", nil},
+		{"unknown identifier", "foo + 1", nil},
+		{"unknown function", "foo(1)", nil},
+		{"wrong arity", "sqrt(1, 2)", nil},
+		{"not finite", "sqrt(-1)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvalExpression(tt.expr)
+			if err == nil {
+				t.Fatalf("EvalExpression(%q) = %v, want error", tt.expr, got)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("EvalExpression(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculatorToolExecute(t *testing.T) {
+	out, err := NewCalculatorTool().Execute(context.Background(), json.RawMessage(`{"expression": "2*(3+4)"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	var result struct {
+		Result     float64 `json:"result"`
+		Expression string  `json:"expression"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if result.Result != 14 || result.Expression != "2*(3+4)" {
+		t.Errorf("Execute = %s, want result 14 for 2*(3+4)", out)
+	}
+
+	if _, err := NewCalculatorTool().Execute(context.Background(), json.RawMessage(`{"expression": 1}`)); err == nil {
+		t.Error("Execute with non-string expression: want error")
+	}
+}
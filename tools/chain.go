@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ChainTool runs a sequence of tools, feeding each tool's output to the next
+// as {"content": "<output>"}.
+type ChainTool struct {
+	name        string
+	description string
+	steps       []Tool
+}
+
+// Chain composes tools into a single tool. The chain accepts the first tool's
+// parameters and returns the last tool's output.
+func Chain(name, description string, tools ...Tool) Tool {
+	return &ChainTool{
+		name:        name,
+		description: description,
+		steps:       tools,
+	}
+}
+
+func (c *ChainTool) Name() string {
+	return c.name
+}
+
+func (c *ChainTool) Description() string {
+	return c.description
+}
+
+func (c *ChainTool) Parameters() json.RawMessage {
+	if len(c.steps) == 0 {
+		return json.RawMessage(`{"type": "object", "properties": {}}`)
+	}
+	return c.steps[0].Parameters()
+}
+
+func (c *ChainTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	if len(c.steps) == 0 {
+		return "", fmt.Errorf("chain %s has no tools", c.name)
+	}
+
+	var output string
+	for i, step := range c.steps {
+		if i > 0 {
+			next, err := json.Marshal(map[string]string{"content": output})
+			if err != nil {
+				return "", fmt.Errorf("chain %s step %d (%s): marshal args: %w", c.name, i, step.Name(), err)
+			}
+			args = next
+		}
+
+		result, err := step.Execute(ctx, args)
+		if err != nil {
+			return "", fmt.Errorf("chain %s step %d (%s): %w", c.name, i, step.Name(), err)
+		}
+		output = result
+	}
+	return output, nil
+}
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// maxCodeExecOutput caps the combined stdout and stderr returned to the model.
+const maxCodeExecOutput = 4 << 10
+
+type codeRuntime struct {
+	command  string
+	args     []string // placed before the source file
+	ext      string
+	memoryMB uint64        // default address space limit
+	timeout  time.Duration // default wall clock and CPU limit
+}
+
+var codeRuntimes = map[string]codeRuntime{
+	"python":  {"python3", nil, ".py", 512, 10 * time.Second},
+	"python3": {"python3", nil, ".py", 512, 10 * time.Second},
+	"go":      {"go", []string{"run"}, ".go", 2048, 60 * time.Second}, // the compiler reserves ~1.5GB and builds the standard library into a fresh cache
+	"node":    {"node", nil, ".js", 512, 10 * time.Second},
+	"bash":    {"bash", nil, ".sh", 512, 10 * time.Second},
+	"sh":      {"sh", nil, ".sh", 512, 10 * time.Second},
+}
+
+// CodeExecConfig configures a code_exec tool.
+type CodeExecConfig struct {
+	AllowedLanguages []string // python, python3, go, node, bash or sh; others are ignored
+	TimeoutSeconds   int      // Optional: per-run wall clock and CPU limit (default: 10, go: 60)
+	WorkDir          string   // Optional: parent of each run's temp dir (default: os.TempDir())
+	MaxMemoryMB      int      // Optional: address space limit of the child process (default: 512, go: 2048)
+}
+
+// CodeExecTool runs code snippets in a sandboxed subprocess so agents can
+// check the code they write. It is opt-in: register it explicitly with the
+// languages you want to allow.
+type CodeExecTool struct {
+	langs     []string
+	workDir   string
+	maxMemory uint64        // bytes; 0 uses the language's default
+	Timeout   time.Duration // Per-run wall clock limit (default: 10s, go: 60s)
+}
+
+// NewCodeExecTool creates a code_exec tool restricted to langs with the
+// default limits. See NewCodeExecToolFromConfig for the supported languages.
+func NewCodeExecTool(langs []string) *CodeExecTool {
+	return NewCodeExecToolFromConfig(CodeExecConfig{AllowedLanguages: langs})
+}
+
+// NewCodeExecToolFromConfig creates a code_exec tool from cfg. Supported
+// languages are python (an alias for python3), python3, go, node, bash and sh.
+func NewCodeExecToolFromConfig(cfg CodeExecConfig) *CodeExecTool {
+	allowed := make([]string, 0, len(cfg.AllowedLanguages))
+	for _, lang := range cfg.AllowedLanguages {
+		if _, ok := codeRuntimes[lang]; ok && !slices.Contains(allowed, lang) {
+			allowed = append(allowed, lang)
+		}
+	}
+
+	var timeout time.Duration
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return &CodeExecTool{
+		langs:     allowed,
+		workDir:   cfg.WorkDir,
+		maxMemory: uint64(max(cfg.MaxMemoryMB, 0)) << 20,
+		Timeout:   timeout,
+	}
+}
+
+func (t *CodeExecTool) Name() string {
+	return "code_exec"
+}
+
+func (t *CodeExecTool) Description() string {
+	return fmt.Sprintf("Runs a code snippet without network access and returns its combined stdout and stderr "+
+		"(truncated to %d bytes). Allowed languages: %v", maxCodeExecOutput, t.langs)
+}
+
+func (t *CodeExecTool) Parameters() json.RawMessage {
+	langs, _ := json.Marshal(t.langs)
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"language": {
+				"type": "string",
+				"enum": %s,
+				"description": "Language of the snippet"
+			},
+			"code": {
+				"type": "string",
+				"description": "Source code to run"
+			}
+		},
+		"required": ["language", "code"]
+	}`, langs))
+}
+
+func (t *CodeExecTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var params struct {
+		Language string `json:"language"`
+		Code     string `json:"code"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if !slices.Contains(t.langs, params.Language) {
+		return "", fmt.Errorf("language %q is not allowed", params.Language)
+	}
+	rt := codeRuntimes[params.Language]
+
+	dir, err := os.MkdirTemp(t.workDir, "fissio-exec-")
+	if err != nil {
+		return "", fmt.Errorf("create work dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "main"+rt.ext)
+	if err := os.WriteFile(file, []byte(params.Code), 0o600); err != nil {
+		return "", fmt.Errorf("write source: %w", err)
+	}
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = rt.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out := &cappedBuffer{limit: maxCodeExecOutput}
+	cmd := exec.CommandContext(ctx, rt.command, append(slices.Clone(rt.args), file)...)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}
+	if params.Language == "go" {
+		cmd.Env = append(cmd.Env, goEnv(dir)...)
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	memory := t.maxMemory
+	if memory == 0 {
+		memory = rt.memoryMB << 20
+	}
+	err = runSandboxed(cmd, sandboxLimits{
+		cpuSeconds:  uint64(max(timeout/time.Second, 1)),
+		memoryBytes: memory,
+	})
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("code_exec exceeded %s: %w", timeout, core.ErrTimeout)
+	}
+
+	result := out.String()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		// A failing program is a result the model should see, not a tool error
+		result += fmt.Sprintf("\n[exit status %d]", exitErr.ExitCode())
+	case err != nil:
+		return "", fmt.Errorf("run %s: %w", params.Language, err)
+	}
+	if out.truncated {
+		result += "\n[output truncated]"
+	}
+	return result, nil
+}
+
+// goEnv lets `go run` work without network access: it never downloads
+// toolchains or modules. Each run gets its own build cache under dir, so one
+// run's code cannot read or poison what another run, or the server, builds.
+func goEnv(dir string) []string {
+	return []string{
+		"GOPATH=" + filepath.Join(dir, "go"),
+		"GOCACHE=" + filepath.Join(dir, "gocache"),
+		"GOTOOLCHAIN=local",
+		"GOPROXY=off",
+	}
+}
+
+// sandboxLimits are the resource limits applied to a code_exec child process.
+type sandboxLimits struct {
+	cpuSeconds  uint64
+	memoryBytes uint64
+}
+
+// cappedBuffer keeps the first limit bytes written and discards the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,106 @@
+//go:build linux
+
+package tools
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// rlimits returns the resource limits applied to a code_exec child process.
+func (l sandboxLimits) rlimits() map[int]uint64 {
+	return map[int]uint64{
+		syscall.RLIMIT_CPU:    l.cpuSeconds,
+		syscall.RLIMIT_AS:     l.memoryBytes,
+		syscall.RLIMIT_FSIZE:  16 << 20, // bytes per written file
+		syscall.RLIMIT_NOFILE: 64,
+		rlimitNproc:           64,
+	}
+}
+
+const rlimitNproc = 0x6
+
+// sandboxShimEnv carries the limits to the re-executed binary; its presence
+// turns the process into the shim.
+const sandboxShimEnv = "_FISSIO_SANDBOX_RLIMITS"
+
+func init() {
+	if spec, ok := os.LookupEnv(sandboxShimEnv); ok {
+		runSandboxShim(spec)
+	}
+}
+
+// runSandboxed starts cmd in fresh user and network namespaces, so the child
+// only sees a loopback interface, with limits in place before it runs. The
+// command is started through a shim, this binary re-executed, which sets the
+// limits on itself and then execs the command.
+func runSandboxed(cmd *exec.Cmd, limits sandboxLimits) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find sandbox shim: %w", err)
+	}
+
+	var spec []string
+	for resource, limit := range limits.rlimits() {
+		spec = append(spec, fmt.Sprintf("%d=%d", resource, limit))
+	}
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args...)
+	cmd.Path = self
+	cmd.Env = append(cmd.Env, sandboxShimEnv+"="+strings.Join(spec, ","))
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:                 syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start sandbox: %w", err)
+	}
+	return cmd.Wait()
+}
+
+// runSandboxShim applies the "resource=limit,..." spec to this process and
+// execs os.Args[1] with the arguments after it, never returning. Failures
+// exit with status 126 like a shell that cannot run a command.
+func runSandboxShim(spec string) {
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, sandboxShimEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+
+	err := setRlimits(spec)
+	if err == nil && len(os.Args) < 3 {
+		err = fmt.Errorf("missing command")
+	}
+	if err == nil {
+		err = syscall.Exec(os.Args[1], os.Args[2:], env)
+	}
+	fmt.Fprintf(os.Stderr, "code_exec sandbox: %v\n", err)
+	os.Exit(126)
+}
+
+func setRlimits(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		resource, limit, _ := strings.Cut(entry, "=")
+		r, err := strconv.Atoi(resource)
+		if err != nil {
+			return fmt.Errorf("invalid rlimit %q", entry)
+		}
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid rlimit %q", entry)
+		}
+		if err := syscall.Setrlimit(r, &syscall.Rlimit{Cur: l, Max: l}); err != nil {
+			return fmt.Errorf("set rlimit %d: %w", r, err)
+		}
+	}
+	return nil
+}
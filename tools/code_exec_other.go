@@ -0,0 +1,14 @@
+//go:build !linux
+
+package tools
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// runSandboxed refuses to run code: network isolation and resource limits
+// are only implemented on Linux.
+func runSandboxed(cmd *exec.Cmd, limits sandboxLimits) error {
+	return errors.New("code_exec sandbox is only supported on linux")
+}
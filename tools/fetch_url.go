@@ -1,21 +1,31 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 type FetchURL struct {
 	client *http.Client
+
+	HTMLToText bool // Optional: return extracted text instead of raw HTML (overridable per call)
+	MaxBytes   int  // Optional: response bytes read before conversion (default: 512 KB)
 }
 
 type fetchURLArgs struct {
-	URL     string `json:"url"`
-	Timeout int    `json:"timeout,omitempty"`
+	URL        string `json:"url"`
+	Timeout    int    `json:"timeout,omitempty"`
+	HTMLToText *bool  `json:"html_to_text,omitempty"`
+	MaxBytes   int    `json:"max_bytes,omitempty"`
 }
 
 func NewFetchURL() *FetchURL {
@@ -29,7 +39,7 @@ func (f *FetchURL) Name() string {
 }
 
 func (f *FetchURL) Description() string {
-	return "Fetches content from a URL and returns the response body"
+	return "Fetches content from a URL and returns the response body, optionally as plain text extracted from HTML"
 }
 
 func (f *FetchURL) Parameters() json.RawMessage {
@@ -43,6 +53,14 @@ func (f *FetchURL) Parameters() json.RawMessage {
 			"timeout": {
 				"type": "integer",
 				"description": "Timeout in seconds (default: 30)"
+			},
+			"html_to_text": {
+				"type": "boolean",
+				"description": "Return the text of paragraphs, headings, list items and table cells instead of raw HTML"
+			},
+			"max_bytes": {
+				"type": "integer",
+				"description": "Maximum response bytes to read (default: 524288)"
 			}
 		},
 		"required": ["url"]
@@ -60,6 +78,19 @@ func (f *FetchURL) Execute(ctx context.Context, args json.RawMessage) (string, e
 		timeout = time.Duration(params.Timeout) * time.Second
 	}
 
+	maxBytes := f.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 512 << 10
+	}
+	if params.MaxBytes > 0 {
+		maxBytes = min(params.MaxBytes, maxBytes)
+	}
+
+	toText := f.HTMLToText
+	if params.HTMLToText != nil {
+		toText = *params.HTMLToText
+	}
+
 	client := &http.Client{Timeout: timeout}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params.URL, nil)
 	if err != nil {
@@ -72,14 +103,84 @@ func (f *FetchURL) Execute(ctx context.Context, args json.RawMessage) (string, e
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)))
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if toText && strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return htmlToText(body)
+	}
 	return string(body), nil
 }
 
+// textBlocks are the elements whose text htmlToText extracts, one per line.
+var textBlocks = map[atom.Atom]bool{
+	atom.P: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Li: true, atom.Td: true,
+}
+
+var hiddenElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+}
+
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Code: true, atom.Em: true, atom.I: true,
+	atom.Mark: true, atom.Small: true, atom.Span: true, atom.Strong: true, atom.Sub: true, atom.Sup: true, atom.U: true,
+}
+
+// htmlToText extracts the text of textBlocks elements in document order with
+// whitespace collapsed. Pages without any such element fall back to all body text.
+func htmlToText(body []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+
+	var lines []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && textBlocks[n.DataAtom] {
+			if text := nodeText(n); text != "" {
+				lines = append(lines, text)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if len(lines) == 0 {
+		return nodeText(doc), nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// nodeText returns the visible text under n with whitespace collapsed.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+		case n.Type == html.ElementNode && hiddenElements[n.DataAtom]:
+			return
+		case n.Type == html.ElementNode && !inlineElements[n.DataAtom]:
+			// Separate block-level content so adjacent cells and lines don't run together.
+			sb.WriteByte(' ')
+			defer sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
 func init() {
 	Register(NewFetchURL())
 }
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// FileReadTool reads local files under a fixed set of root directories.
+type FileReadTool struct {
+	roots    []string
+	MaxBytes int64 // Bytes returned per file (default: 100 KB)
+}
+
+// NewFileReadTool creates a read_file tool confined to allowedPaths. Roots
+// that cannot be resolved are dropped.
+func NewFileReadTool(allowedPaths []string) *FileReadTool {
+	roots := make([]string, 0, len(allowedPaths))
+	for _, p := range allowedPaths {
+		root, err := resolvePath(p)
+		if err != nil {
+			continue
+		}
+		roots = append(roots, root)
+	}
+	return &FileReadTool{
+		roots:    roots,
+		MaxBytes: 100 << 10,
+	}
+}
+
+func (t *FileReadTool) Name() string {
+	return "read_file"
+}
+
+func (t *FileReadTool) Description() string {
+	return "Reads a local file and returns its content. Text is returned as UTF-8, binary files as base64."
+}
+
+func (t *FileReadTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {
+				"type": "string",
+				"description": "Path of the file to read, e.g. ./schema.sql"
+			}
+		},
+		"required": ["path"]
+	}`)
+}
+
+func (t *FileReadTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	path, err := t.allow(params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", params.Path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat %s: %w", params.Path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", params.Path)
+	}
+
+	maxBytes := t.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 100 << 10
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxBytes))
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", params.Path, err)
+	}
+	truncated := info.Size() > maxBytes
+
+	result := map[string]any{
+		"path":      params.Path,
+		"size":      info.Size(),
+		"truncated": truncated,
+	}
+	if text, ok := asText(data, truncated); ok {
+		result["encoding"] = "utf-8"
+		result["content"] = text
+	} else {
+		result["encoding"] = "base64"
+		result["content"] = base64.StdEncoding.EncodeToString(data)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshal result: %w", err)
+	}
+	return string(out), nil
+}
+
+// allow resolves path and checks that it stays inside one of the roots.
+func (t *FileReadTool) allow(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("path %q must not contain ..", path)
+		}
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	for _, root := range t.roots {
+		if resolved == root || strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("path %q is outside the allowed directories", path)
+}
+
+// resolvePath returns the cleaned absolute path with symlinks followed, so a
+// link inside a root cannot point outside it.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// asText reports whether data is UTF-8 text. A truncated read may end
+// mid-rune, so an incomplete trailing sequence is dropped rather than
+// treated as binary.
+func asText(data []byte, truncated bool) (string, bool) {
+	if truncated {
+		for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+			if utf8.Valid(data) {
+				break
+			}
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) || strings.ContainsRune(string(data), 0) {
+		return "", false
+	}
+	return string(data), true
+}
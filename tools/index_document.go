@@ -30,7 +30,8 @@ func (t *IndexDocumentTool) Name() string {
 }
 
 func (t *IndexDocumentTool) Description() string {
-	return "Add a document to the knowledge base for future similarity searches."
+	return "Add a document to the knowledge base for future similarity searches, " +
+		"or remove documents whose metadata matches delete_where."
 }
 
 func (t *IndexDocumentTool) Parameters() json.RawMessage {
@@ -48,22 +49,38 @@ func (t *IndexDocumentTool) Parameters() json.RawMessage {
 			"metadata": {
 				"type": "object",
 				"description": "Optional metadata to store with the document"
+			},
+			"delete_where": {
+				"type": "object",
+				"description": "Metadata key/value pairs; deletes every matching document instead of indexing"
 			}
-		},
-		"required": ["id", "content"]
+		}
 	}`)
 }
 
 func (t *IndexDocumentTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var req struct {
-		ID       string         `json:"id"`
-		Content  string         `json:"content"`
-		Metadata map[string]any `json:"metadata"`
+		ID          string         `json:"id"`
+		Content     string         `json:"content"`
+		Metadata    map[string]any `json:"metadata"`
+		DeleteWhere map[string]any `json:"delete_where"`
 	}
 	if err := json.Unmarshal(args, &req); err != nil {
 		return "", fmt.Errorf("parse args: %w", err)
 	}
 
+	if len(req.DeleteWhere) > 0 {
+		n, err := t.store.DeleteWhere(ctx, req.DeleteWhere)
+		if err != nil {
+			return "", fmt.Errorf("delete where: %w", err)
+		}
+		return fmt.Sprintf("Deleted %d document(s).", n), nil
+	}
+
+	if req.ID == "" || req.Content == "" {
+		return "", fmt.Errorf("id and content are required")
+	}
+
 	// Generate embedding
 	resp, err := t.embedder.Embed(ctx, t.model, req.Content)
 	if err != nil {
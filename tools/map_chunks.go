@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hubenschmidt/go-fissio/llm"
+	"github.com/hubenschmidt/go-fissio/vector"
+)
+
+const defaultMapPrompt = "Extract the key points from this section of a longer document."
+
+// MapChunksTool splits long input into chunks and runs a prompt over each
+// chunk with a separate LLM call, so documents larger than the context window
+// can be processed.
+type MapChunksTool struct {
+	client      llm.Client
+	model       string
+	concurrency int
+}
+
+// NewMapChunksTool creates a map_chunks tool that calls model through client.
+func NewMapChunksTool(client llm.Client, model string) *MapChunksTool {
+	return &MapChunksTool{
+		client:      client,
+		model:       model,
+		concurrency: 4,
+	}
+}
+
+func (t *MapChunksTool) Name() string {
+	return "map_chunks"
+}
+
+func (t *MapChunksTool) Description() string {
+	return "Split a long text into chunks and apply an instruction to each chunk separately. " +
+		"Returns the per-chunk results in order."
+}
+
+func (t *MapChunksTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"input": {
+				"type": "string",
+				"description": "The text to split and process"
+			},
+			"delimiter": {
+				"type": "string",
+				"description": "Preferred split point between chunks (default: blank line)"
+			},
+			"max_tokens": {
+				"type": "integer",
+				"description": "Approximate maximum tokens per chunk (default: 2000)"
+			},
+			"prompt": {
+				"type": "string",
+				"description": "Instruction applied to each chunk"
+			}
+		},
+		"required": ["input"]
+	}`)
+}
+
+func (t *MapChunksTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var req struct {
+		Input     string `json:"input"`
+		Delimiter string `json:"delimiter"`
+		MaxTokens int    `json:"max_tokens"`
+		Prompt    string `json:"prompt"`
+	}
+	if err := json.Unmarshal(args, &req); err != nil {
+		return "", fmt.Errorf("parse args: %w", err)
+	}
+
+	if req.Delimiter == "" {
+		req.Delimiter = "\n\n"
+	}
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = 2000
+	}
+	if req.Prompt == "" {
+		req.Prompt = defaultMapPrompt
+	}
+
+	// Prefer the delimiter, falling back to finer splits for oversized sections
+	splitter := &vector.RecursiveTextSplitter{
+		ChunkSize:  req.MaxTokens,
+		Separators: append([]string{req.Delimiter}, vector.DefaultSeparators...),
+		Length:     vector.EstimateTokens,
+	}
+	chunks := splitter.Split(req.Input)
+	if len(chunks) == 0 {
+		return "No input to process.", nil
+	}
+
+	results, err := t.mapChunks(ctx, req.Prompt, chunks)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, r := range results {
+		sb.WriteString(fmt.Sprintf("--- Chunk %d of %d ---\n", i+1, len(results)))
+		sb.WriteString(r)
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+func (t *MapChunksTool) mapChunks(ctx context.Context, prompt string, chunks []string) ([]string, error) {
+	results := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, t.concurrency)
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp, err := t.client.Chat(ctx, t.model, prompt, chunk)
+			if err != nil {
+				errs[i] = fmt.Errorf("map chunk %d: %w", i+1, err)
+				return
+			}
+			results[i] = resp.Content
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
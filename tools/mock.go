@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// MockTool is a Tool with canned responses for testing worker nodes.
+// Responses is keyed by the compact JSON of the call arguments, e.g.
+// `{"query":"go"}`; the "*" key matches any arguments.
+type MockTool struct {
+	ToolName   string
+	ToolDesc   string
+	ToolParams json.RawMessage // Optional: defaults to an empty object schema
+	Responses  map[string]string
+
+	mu    sync.Mutex
+	calls []json.RawMessage
+}
+
+func (m *MockTool) Name() string {
+	return m.ToolName
+}
+
+func (m *MockTool) Description() string {
+	return m.ToolDesc
+}
+
+func (m *MockTool) Parameters() json.RawMessage {
+	if len(m.ToolParams) > 0 {
+		return m.ToolParams
+	}
+	return json.RawMessage(`{"type": "object", "properties": {}}`)
+}
+
+func (m *MockTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	key := string(args)
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, args); err == nil {
+		key = compact.String()
+	}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, append(json.RawMessage(nil), args...))
+	m.mu.Unlock()
+
+	if resp, ok := m.Responses[key]; ok {
+		return resp, nil
+	}
+	if resp, ok := m.Responses["*"]; ok {
+		return resp, nil
+	}
+	return "", fmt.Errorf("mock tool %s: no response for args %s", m.ToolName, key)
+}
+
+// Calls returns the arguments of every Execute call in order.
+func (m *MockTool) Calls() []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]json.RawMessage(nil), m.calls...)
+}
+
+// AssertCalledWith fails t unless some call's arguments are JSON-equal to args.
+func (m *MockTool) AssertCalledWith(t testing.TB, args json.RawMessage) {
+	t.Helper()
+
+	var want any
+	if err := json.Unmarshal(args, &want); err != nil {
+		t.Fatalf("mock tool %s: invalid expected args %s: %v", m.ToolName, args, err)
+		return
+	}
+
+	calls := m.Calls()
+	for _, call := range calls {
+		var got any
+		if json.Unmarshal(call, &got) == nil && reflect.DeepEqual(got, want) {
+			return
+		}
+	}
+	t.Errorf("mock tool %s: not called with %s; calls: %s", m.ToolName, args, calls)
+}
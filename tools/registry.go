@@ -23,6 +23,26 @@ func (r *Registry) Register(t Tool) {
 	r.tools[t.Name()] = t
 }
 
+// Unregister removes the named tool and reports whether it was registered.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.tools[name]
+	delete(r.tools, name)
+	return ok
+}
+
+// Replace swaps in t for the tool registered under the same name, returning
+// the previous implementation if there was one. Callers never observe the
+// name unregistered in between.
+func (r *Registry) Replace(t Tool) (old Tool, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok = r.tools[t.Name()]
+	r.tools[t.Name()] = t
+	return old, ok
+}
+
 func (r *Registry) Get(name string) (Tool, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -64,6 +84,30 @@ func (r *Registry) Schemas(names []string) ([]core.ToolSchema, error) {
 	return ToSchemas(tools), nil
 }
 
+// ScopedRegistry returns a registry containing only the allowed tools from parent.
+// Unknown names are ignored.
+func ScopedRegistry(parent *Registry, allowed []string) *Registry {
+	scoped := NewRegistry()
+	for _, name := range allowed {
+		if t, ok := parent.Get(name); ok {
+			scoped.Register(t)
+		}
+	}
+	return scoped
+}
+
+// Clone returns an independent registry holding the same tools as r.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clone := NewRegistry()
+	for name, t := range r.tools {
+		clone.tools[name] = t
+	}
+	return clone
+}
+
 var DefaultRegistry = NewRegistry()
 
 func Register(t Tool) {
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryExhaustedError is returned by a Retry tool once every attempt failed.
+// It unwraps to the last attempt's error.
+type RetryExhaustedError struct {
+	Tool     string
+	Attempts []error // one per attempt, in order
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("tool %s failed after %d attempts: %v", e.Tool, len(e.Attempts), e.Unwrap())
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// RetryTool re-runs failed Execute calls of the wrapped tool.
+type RetryTool struct {
+	inner       Tool
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// Retry wraps t so failed calls are retried up to maxAttempts times in total.
+// The wait before retry n is backoff * 2^(n-1), with up to 50% random jitter
+// added. Retrying stops early once the context is done.
+func Retry(t Tool, maxAttempts int, backoff time.Duration) Tool {
+	return &RetryTool{inner: t, maxAttempts: max(maxAttempts, 1), backoff: backoff}
+}
+
+func (t *RetryTool) Name() string {
+	return t.inner.Name()
+}
+
+func (t *RetryTool) Description() string {
+	return t.inner.Description()
+}
+
+func (t *RetryTool) Parameters() json.RawMessage {
+	return t.inner.Parameters()
+}
+
+func (t *RetryTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var attempts []error
+	for i := 0; i < t.maxAttempts; i++ {
+		if i > 0 {
+			timer := time.NewTimer(t.delay(i))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return "", ctx.Err()
+			}
+		}
+
+		output, err := t.inner.Execute(ctx, args)
+		if err == nil {
+			return output, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		attempts = append(attempts, err)
+	}
+	return "", &RetryExhaustedError{Tool: t.inner.Name(), Attempts: attempts}
+}
+
+// delay returns the wait before the given retry (1-based).
+func (t *RetryTool) delay(retry int) time.Duration {
+	d := t.backoff << (retry - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d + rand.N(d/2+1)
+}
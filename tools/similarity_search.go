@@ -15,6 +15,7 @@ type SimilaritySearchTool struct {
 	store    vector.Store
 	embedder llm.EmbeddingClient
 	model    string
+	reranker vector.Reranker
 }
 
 // NewSimilaritySearchTool creates a new similarity search tool.
@@ -26,6 +27,12 @@ func NewSimilaritySearchTool(store vector.Store, embedder llm.EmbeddingClient, m
 	}
 }
 
+// WithReranker enables the rerank parameter, which reorders candidates with r.
+func (t *SimilaritySearchTool) WithReranker(r vector.Reranker) *SimilaritySearchTool {
+	t.reranker = r
+	return t
+}
+
 func (t *SimilaritySearchTool) Name() string {
 	return "similarity_search"
 }
@@ -45,6 +52,14 @@ func (t *SimilaritySearchTool) Parameters() json.RawMessage {
 			"top_k": {
 				"type": "integer",
 				"description": "Maximum number of results to return (default: 5)"
+			},
+			"min_score": {
+				"type": "number",
+				"description": "Minimum similarity score (0-1) for a result to be returned"
+			},
+			"rerank": {
+				"type": "boolean",
+				"description": "Rerank candidates for higher precision (slower)"
 			}
 		},
 		"required": ["query"]
@@ -53,8 +68,10 @@ func (t *SimilaritySearchTool) Parameters() json.RawMessage {
 
 func (t *SimilaritySearchTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var req struct {
-		Query string `json:"query"`
-		TopK  int    `json:"top_k"`
+		Query    string  `json:"query"`
+		TopK     int     `json:"top_k"`
+		MinScore float64 `json:"min_score"`
+		Rerank   bool    `json:"rerank"`
 	}
 	if err := json.Unmarshal(args, &req); err != nil {
 		return "", fmt.Errorf("parse args: %w", err)
@@ -65,17 +82,35 @@ func (t *SimilaritySearchTool) Execute(ctx context.Context, args json.RawMessage
 	}
 
 	// Generate embedding for query
-	resp, err := t.embedder.Embed(ctx, t.model, req.Query)
+	resp, err := t.embedder.Embed(llm.WithEmbedInputType(ctx, llm.EmbedSearchQuery), t.model, req.Query)
 	if err != nil {
 		return "", fmt.Errorf("embed query: %w", err)
 	}
 
-	// Search vector store
-	results, err := t.store.Search(ctx, resp.Embedding, req.TopK)
+	// Search vector store, over-fetching candidates when reranking
+	rerank := req.Rerank && t.reranker != nil
+	opts := vector.SearchOptions{TopK: req.TopK, MinScore: -1}
+	if rerank {
+		opts.TopK = req.TopK * 3
+	}
+	if req.MinScore > 0 {
+		opts.MinScore = req.MinScore
+	}
+	// Query text lets hybrid stores add keyword matches
+	results, err := t.store.SearchWithThreshold(vector.WithQueryText(ctx, req.Query), resp.Embedding, opts)
 	if err != nil {
 		return "", fmt.Errorf("search: %w", err)
 	}
 
+	if rerank {
+		if results, err = t.reranker.Rerank(ctx, req.Query, results); err != nil {
+			return "", fmt.Errorf("rerank: %w", err)
+		}
+		if len(results) > req.TopK {
+			results = results[:req.TopK]
+		}
+	}
+
 	if len(results) == 0 {
 		return "No similar documents found.", nil
 	}
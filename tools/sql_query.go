@@ -0,0 +1,302 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SQLQueryOptions configures a SQLQueryTool.
+type SQLQueryOptions struct {
+	AllowedTables []string      // Optional: tables queries may reference (empty = any); "users" also allows "public.users", "public.users" allows only itself
+	ReadOnly      bool          // Reject statements that modify data or schema
+	Timeout       time.Duration // Per-query limit (default: 10s)
+	MaxRows       int           // Rows returned per query (default: 100)
+}
+
+// writeKeywords are rejected in ReadOnly mode.
+var writeKeywords = []string{
+	"INSERT", "UPDATE", "DELETE", "DROP", "ALTER", "CREATE", "TRUNCATE",
+	"REPLACE", "MERGE", "GRANT", "REVOKE", "ATTACH", "DETACH", "PRAGMA", "VACUUM", "COPY",
+}
+
+// SQLQueryTool runs SQL against a database and returns rows as JSON.
+type SQLQueryTool struct {
+	db   *sql.DB
+	opts SQLQueryOptions
+}
+
+func NewSQLQueryTool(db *sql.DB, opts SQLQueryOptions) *SQLQueryTool {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = 100
+	}
+	allowed := make([]string, len(opts.AllowedTables))
+	for i, table := range opts.AllowedTables {
+		allowed[i] = strings.ToLower(table)
+	}
+	opts.AllowedTables = allowed
+	return &SQLQueryTool{db: db, opts: opts}
+}
+
+func (t *SQLQueryTool) Name() string {
+	return "sql_query"
+}
+
+func (t *SQLQueryTool) Description() string {
+	desc := "Runs a single SQL statement against the database and returns the rows as a JSON array."
+	if t.opts.ReadOnly {
+		desc += " Only read queries are allowed."
+	}
+	if len(t.opts.AllowedTables) > 0 {
+		desc += " Allowed tables: " + strings.Join(t.opts.AllowedTables, ", ") + "."
+	}
+	return desc
+}
+
+func (t *SQLQueryTool) Parameters() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "The SQL statement to run"
+			}
+		},
+		"required": ["query"]
+	}`)
+}
+
+func (t *SQLQueryTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if err := t.check(params.Query); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.opts.Timeout)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx, params.Query)
+	if err != nil {
+		return "", fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	result, err := scanRows(rows, t.opts.MaxRows)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshal rows: %w", err)
+	}
+	return string(out), nil
+}
+
+// check applies the statement rules: one statement, no writes in ReadOnly
+// mode, and only whitelisted tables after FROM, JOIN, INTO, UPDATE or TABLE.
+// It is a keyword scan, not a SQL parser; pair it with a read-only database
+// role for untrusted input.
+func (t *SQLQueryTool) check(query string) error {
+	words, statements := sqlWords(query)
+	if len(words) == 0 {
+		return fmt.Errorf("query is empty")
+	}
+	if statements > 1 {
+		return fmt.Errorf("only one statement per query is allowed")
+	}
+
+	// CTE names are defined by the query itself
+	ctes := make(map[string]bool)
+	for i := 1; i+1 < len(words); i++ {
+		if strings.EqualFold(words[i+1], "AS") && (strings.EqualFold(words[i-1], "WITH") || words[i-1] == ",") {
+			ctes[strings.ToLower(words[i])] = true
+		}
+	}
+
+	for i, w := range words {
+		upper := strings.ToUpper(w)
+		isCall := i+1 < len(words) && words[i+1] == "(" // e.g. replace(name, 'a', 'b')
+		if t.opts.ReadOnly && slices.Contains(writeKeywords, upper) && !isCall {
+			return fmt.Errorf("%s statements are not allowed in read-only mode", upper)
+		}
+		if len(t.opts.AllowedTables) == 0 || i+1 >= len(words) {
+			continue
+		}
+		switch upper {
+		case "FROM", "JOIN", "INTO", "UPDATE", "TABLE":
+			for _, name := range tableList(words, i+1) {
+				table := strings.ToLower(name)
+				if table == "(" || ctes[table] {
+					continue
+				}
+				if !t.tableAllowed(table) {
+					return fmt.Errorf("table %q is not allowed", name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tableAllowed reports whether the lowercased table reference is whitelisted,
+// either exactly or, for an unqualified entry, by its last dotted segment.
+func (t *SQLQueryTool) tableAllowed(table string) bool {
+	if slices.Contains(t.opts.AllowedTables, table) {
+		return true
+	}
+	i := strings.LastIndexByte(table, '.')
+	return i >= 0 && slices.Contains(t.opts.AllowedTables, table[i+1:])
+}
+
+// tableList returns the comma-separated table references starting at
+// words[i], skipping aliases, as in "FROM a x, b AS y".
+func tableList(words []string, i int) []string {
+	var tables []string
+	for i < len(words) {
+		tables = append(tables, words[i])
+		i++
+		for i < len(words) && words[i] != "," && !isClauseKeyword(words[i]) {
+			i++ // alias
+		}
+		if i >= len(words) || words[i] != "," {
+			break
+		}
+		i++
+	}
+	return tables
+}
+
+var clauseKeywords = []string{
+	"WHERE", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "ON", "USING",
+	"GROUP", "ORDER", "HAVING", "LIMIT", "OFFSET", "UNION", "INTERSECT", "EXCEPT",
+	"SET", "VALUES", "SELECT", "RETURNING", "WINDOW", "(",
+}
+
+func isClauseKeyword(word string) bool {
+	return slices.Contains(clauseKeywords, strings.ToUpper(word))
+}
+
+// sqlWords splits query into identifiers, keywords and the punctuation "(",
+// "," — skipping string literals and comments — and counts the statements.
+func sqlWords(query string) ([]string, int) {
+	var words []string
+	statements := 0
+	pending := false
+
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end
+			}
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+		case ch == '\'':
+			i = skipQuoted(query, i, '\'')
+			pending = true
+		case ch == '"' || ch == '`':
+			end := skipQuoted(query, i, ch)
+			words = append(words, query[i+1:max(end-1, i+1)])
+			pending = true
+			i = end
+		case ch == ';':
+			if pending {
+				statements++
+				pending = false
+			}
+			i++
+		case ch == '(' || ch == ',':
+			words = append(words, string(ch))
+			pending = true
+			i++
+		case isSQLIdent(ch):
+			start := i
+			for i < len(query) && (isSQLIdent(query[i]) || query[i] == '.') {
+				i++
+			}
+			words = append(words, query[start:i])
+			pending = true
+		default:
+			if ch > ' ' {
+				pending = true
+			}
+			i++
+		}
+	}
+	if pending {
+		statements++
+	}
+	return words, statements
+}
+
+// skipQuoted returns the index just past the literal starting at i, treating
+// a doubled quote as an escaped one.
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != quote {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+func isSQLIdent(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+func scanRows(rows *sql.Rows, maxRows int) ([]map[string]any, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("columns: %w", err)
+	}
+
+	result := []map[string]any{}
+	for rows.Next() && len(result) < maxRows {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
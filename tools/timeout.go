@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/core"
+)
+
+// TimeoutTool bounds each Execute call of the wrapped tool to a fixed duration.
+type TimeoutTool struct {
+	inner   Tool
+	timeout time.Duration
+}
+
+// Timeout wraps t so each call fails with core.ErrTimeout once d elapses,
+// even if t ignores context cancellation.
+func Timeout(t Tool, d time.Duration) Tool {
+	return &TimeoutTool{inner: t, timeout: d}
+}
+
+func (t *TimeoutTool) Name() string {
+	return t.inner.Name()
+}
+
+func (t *TimeoutTool) Description() string {
+	return t.inner.Description()
+}
+
+func (t *TimeoutTool) Parameters() json.RawMessage {
+	return t.inner.Parameters()
+}
+
+func (t *TimeoutTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := t.inner.Execute(ctx, args)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", t.timeoutError()
+		}
+		return r.output, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", t.timeoutError()
+		}
+		return "", ctx.Err()
+	}
+}
+
+func (t *TimeoutTool) timeoutError() error {
+	return fmt.Errorf("tool %s exceeded %s: %w", t.inner.Name(), t.timeout, core.ErrTimeout)
+}
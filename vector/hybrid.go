@@ -0,0 +1,312 @@
+package vector
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// rrfK dampens the contribution of top ranks in Reciprocal Rank Fusion.
+const rrfK = 60
+
+type queryTextKey struct{}
+
+// WithQueryText attaches the raw query text to ctx so HybridStore.Search can
+// run keyword search alongside the embedding search.
+func WithQueryText(ctx context.Context, query string) context.Context {
+	return context.WithValue(ctx, queryTextKey{}, query)
+}
+
+func queryText(ctx context.Context) string {
+	query, _ := ctx.Value(queryTextKey{}).(string)
+	return query
+}
+
+// HybridStore combines a dense vector Store with an in-process BM25 keyword
+// index and merges both result lists with weighted Reciprocal Rank Fusion.
+// Result scores are fused RRF scores, not cosine similarities.
+type HybridStore struct {
+	dense Store
+	alpha float64
+	index *bm25Index
+}
+
+// NewHybridStore wraps dense with a keyword index. alpha weights the fusion:
+// 0 uses only BM25 ranks, 1 only vector ranks. Documents already in dense are
+// not keyword-searchable until Reindex is called.
+func NewHybridStore(dense Store, alpha float64) *HybridStore {
+	return &HybridStore{
+		dense: dense,
+		alpha: min(max(alpha, 0), 1),
+		index: newBM25Index(),
+	}
+}
+
+// Reindex rebuilds the keyword index from every document in the dense store.
+func (h *HybridStore) Reindex(ctx context.Context) error {
+	docs, _, err := h.dense.List(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+	h.index.reset()
+	h.index.add(docs)
+	return nil
+}
+
+// Upsert stores documents in the dense store and indexes their content.
+func (h *HybridStore) Upsert(ctx context.Context, docs []Document) error {
+	if err := h.dense.Upsert(ctx, docs); err != nil {
+		return err
+	}
+	h.index.add(docs)
+	return nil
+}
+
+// Search finds documents by embedding and, when ctx carries query text
+// (see WithQueryText), by keyword.
+func (h *HybridStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	return h.SearchWithThreshold(ctx, embedding, SearchOptions{TopK: topK, MinScore: -1})
+}
+
+// SearchWithThreshold runs the dense and keyword searches in parallel and fuses them.
+// opts.MinScore applies to the dense results only.
+func (h *HybridStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
+	return h.SearchText(ctx, queryText(ctx), embedding, opts)
+}
+
+// SearchText is SearchWithThreshold with explicit query text.
+func (h *HybridStore) SearchText(ctx context.Context, query string, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
+	candidates := opts
+	if opts.TopK > 0 {
+		candidates.TopK = opts.TopK * 2
+	}
+
+	var (
+		wg       sync.WaitGroup
+		dense    []SearchResult
+		denseErr error
+		sparse   []SearchResult
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dense, denseErr = h.dense.SearchWithThreshold(ctx, embedding, candidates)
+	}()
+	if query != "" {
+		sparse = h.index.search(query, candidates)
+	}
+	wg.Wait()
+
+	if denseErr != nil {
+		return nil, denseErr
+	}
+
+	results := fuseRanks(dense, sparse, h.alpha)
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results, nil
+}
+
+// List returns a page of documents from the dense store.
+func (h *HybridStore) List(ctx context.Context, limit, offset int) ([]Document, int, error) {
+	return h.dense.List(ctx, limit, offset)
+}
+
+// Delete removes documents by ID from both stores.
+func (h *HybridStore) Delete(ctx context.Context, ids []string) error {
+	if err := h.dense.Delete(ctx, ids); err != nil {
+		return err
+	}
+	h.index.remove(ids)
+	return nil
+}
+
+// DeleteWhere removes documents matching filter from both stores.
+func (h *HybridStore) DeleteWhere(ctx context.Context, filter map[string]any) (int, error) {
+	n, err := h.dense.DeleteWhere(ctx, filter)
+	if err != nil {
+		return n, err
+	}
+	h.index.removeWhere(filter)
+	return n, nil
+}
+
+// Close closes the dense store.
+func (h *HybridStore) Close() error {
+	return h.dense.Close()
+}
+
+// fuseRanks merges two ranked lists, scoring each document by
+// alpha/(k+denseRank) + (1-alpha)/(k+sparseRank).
+func fuseRanks(dense, sparse []SearchResult, alpha float64) []SearchResult {
+	scores := make(map[string]float64)
+	docs := make(map[string]Document)
+
+	addRanks := func(results []SearchResult, weight float64) {
+		for rank, r := range results {
+			scores[r.Document.ID] += weight / float64(rrfK+rank+1)
+			if _, ok := docs[r.Document.ID]; !ok {
+				docs[r.Document.ID] = r.Document
+			}
+		}
+	}
+	addRanks(dense, alpha)
+	addRanks(sparse, 1-alpha)
+
+	results := make([]SearchResult, 0, len(docs))
+	for id, doc := range docs {
+		results = append(results, SearchResult{Document: doc, Score: scores[id]})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Document.ID < results[j].Document.ID
+	})
+	return results
+}
+
+// bm25Index is an in-memory Okapi BM25 keyword index.
+type bm25Index struct {
+	mu       sync.RWMutex
+	docs     map[string]Document
+	terms    map[string]map[string]int // doc ID -> term -> frequency
+	lengths  map[string]int
+	docFreq  map[string]int
+	totalLen int
+}
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+func newBM25Index() *bm25Index {
+	idx := &bm25Index{}
+	idx.reset()
+	return idx
+}
+
+func (idx *bm25Index) reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = make(map[string]Document)
+	idx.terms = make(map[string]map[string]int)
+	idx.lengths = make(map[string]int)
+	idx.docFreq = make(map[string]int)
+	idx.totalLen = 0
+}
+
+func (idx *bm25Index) add(docs []Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, doc := range docs {
+		idx.removeLocked(doc.ID)
+
+		freqs := make(map[string]int)
+		tokens := tokenize(doc.Content)
+		for _, t := range tokens {
+			freqs[t]++
+		}
+		for t := range freqs {
+			idx.docFreq[t]++
+		}
+
+		idx.docs[doc.ID] = doc
+		idx.terms[doc.ID] = freqs
+		idx.lengths[doc.ID] = len(tokens)
+		idx.totalLen += len(tokens)
+	}
+}
+
+func (idx *bm25Index) remove(ids []string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, id := range ids {
+		idx.removeLocked(id)
+	}
+}
+
+func (idx *bm25Index) removeWhere(filter map[string]any) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id, doc := range idx.docs {
+		if matchesFilter(doc.Metadata, filter) {
+			idx.removeLocked(id)
+		}
+	}
+}
+
+func (idx *bm25Index) removeLocked(id string) {
+	freqs, ok := idx.terms[id]
+	if !ok {
+		return
+	}
+	for t := range freqs {
+		if idx.docFreq[t]--; idx.docFreq[t] == 0 {
+			delete(idx.docFreq, t)
+		}
+	}
+	idx.totalLen -= idx.lengths[id]
+	delete(idx.docs, id)
+	delete(idx.terms, id)
+	delete(idx.lengths, id)
+}
+
+// search ranks documents matching at least one query term by BM25 score.
+func (idx *bm25Index) search(query string, opts SearchOptions) []SearchResult {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	n := len(idx.docs)
+	if n == 0 {
+		return nil
+	}
+	avgLen := float64(idx.totalLen) / float64(n)
+	queryTerms := tokenize(query)
+
+	now := time.Now()
+	var results []SearchResult
+	for id, freqs := range idx.terms {
+		doc := idx.docs[id]
+		if doc.Expired(now) || !matchesFilter(doc.Metadata, opts.Filter) {
+			continue
+		}
+
+		var score float64
+		for _, t := range queryTerms {
+			tf := float64(freqs[t])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[t])
+			idf := math.Log(1 + (float64(n)-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[id])/avgLen
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, SearchResult{Document: doc, Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results
+}
+
+// tokenize lowercases text and splits it into letter/digit runs.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,81 @@
+// Package loader reads files into vector documents.
+package loader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/hubenschmidt/go-fissio/vector"
+)
+
+// PDFLoaderConfig configures a PDFLoader.
+type PDFLoaderConfig struct {
+	SplitByPage bool // Optional: one document per page instead of one for the whole file
+	MaxPages    int  // Optional: read at most this many pages (0 = all)
+	SkipImages  bool // Optional: drop pages with no extractable text, such as scanned images
+}
+
+// PDFLoader extracts plain text from PDF files. It is pure Go and needs no CGO.
+type PDFLoader struct {
+	cfg PDFLoaderConfig
+}
+
+// NewPDFLoader creates a PDF loader.
+func NewPDFLoader(cfg PDFLoaderConfig) *PDFLoader {
+	return &PDFLoader{cfg: cfg}
+}
+
+// Load reads the PDF at path. Each document carries "source" metadata, and
+// per-page documents also carry "page" (1-based). Pass the results to
+// vector.SplitDocument to chunk them before embedding.
+func (l *PDFLoader) Load(ctx context.Context, path string) ([]vector.Document, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open pdf %s: %w", path, err)
+	}
+	defer f.Close()
+
+	numPages := r.NumPage()
+	if l.cfg.MaxPages > 0 && l.cfg.MaxPages < numPages {
+		numPages = l.cfg.MaxPages
+	}
+
+	var docs []vector.Document
+	var pages []string
+	for i := 1; i <= numPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		text, err := r.Page(i).GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("read pdf %s page %d: %w", path, i, err)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" && l.cfg.SkipImages {
+			continue
+		}
+
+		if !l.cfg.SplitByPage {
+			pages = append(pages, text)
+			continue
+		}
+		docs = append(docs, vector.Document{
+			ID:       fmt.Sprintf("%s#page%d", path, i),
+			Content:  text,
+			Metadata: map[string]any{"page": i, "source": path},
+		})
+	}
+
+	if l.cfg.SplitByPage {
+		return docs, nil
+	}
+	return []vector.Document{{
+		ID:       path,
+		Content:  strings.Join(pages, "\n\n"),
+		Metadata: map[string]any{"source": path},
+	}}, nil
+}
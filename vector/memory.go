@@ -2,8 +2,12 @@ package vector
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 	"sort"
 	"sync"
+	"time"
 )
 
 // MemoryStore is an in-memory vector store for development and testing.
@@ -32,32 +36,84 @@ func (s *MemoryStore) Upsert(ctx context.Context, docs []Document) error {
 
 // Search finds documents similar to the given embedding using brute-force cosine similarity.
 func (s *MemoryStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	return s.SearchWithThreshold(ctx, embedding, SearchOptions{TopK: topK, MinScore: -1})
+}
+
+// SearchWithThreshold finds similar documents, dropping those scoring below opts.MinScore
+// or not matching opts.Filter.
+func (s *MemoryStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	results := s.computeSimilarities(embedding)
+	results := s.computeSimilarities(embedding, opts)
 
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Score > results[j].Score
 	})
 
-	if topK > 0 && len(results) > topK {
-		results = results[:topK]
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
 	}
 
 	return results, nil
 }
 
-func (s *MemoryStore) computeSimilarities(embedding []float64) []SearchResult {
+func (s *MemoryStore) computeSimilarities(embedding []float64, opts SearchOptions) []SearchResult {
+	now := time.Now()
 	results := make([]SearchResult, 0, len(s.docs))
 	for _, doc := range s.docs {
-		if len(doc.Embedding) > 0 {
-			results = append(results, SearchResult{Document: doc, Score: CosineSimilarity(embedding, doc.Embedding)})
+		if len(doc.Embedding) == 0 || doc.Expired(now) || !matchesFilter(doc.Metadata, opts.Filter) {
+			continue
 		}
+		score := CosineSimilarity(embedding, doc.Embedding)
+		if score < opts.MinScore {
+			continue
+		}
+		results = append(results, SearchResult{Document: doc, Score: score})
 	}
 	return results
 }
 
+// matchesFilter reports whether metadata holds every filter key with an equal value.
+// Values are compared by their string form so 1 and 1.0 from JSON decoding match.
+func matchesFilter(metadata, filter map[string]any) bool {
+	for k, want := range filter {
+		got, ok := metadata[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// List returns unexpired documents sorted by ID, skipping offset and returning at most limit (0 = all).
+func (s *MemoryStore) List(ctx context.Context, limit, offset int) ([]Document, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	ids := make([]string, 0, len(s.docs))
+	for id, doc := range s.docs {
+		if !doc.Expired(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	total := len(ids)
+	offset = min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(offset+limit, total)
+	}
+
+	docs := make([]Document, 0, end-offset)
+	for _, id := range ids[offset:end] {
+		docs = append(docs, s.docs[id])
+	}
+	return docs, total, nil
+}
+
 // Delete removes documents by ID.
 func (s *MemoryStore) Delete(ctx context.Context, ids []string) error {
 	s.mu.Lock()
@@ -69,6 +125,122 @@ func (s *MemoryStore) Delete(ctx context.Context, ids []string) error {
 	return nil
 }
 
+// DeleteWhere removes documents whose metadata matches filter.
+func (s *MemoryStore) DeleteWhere(ctx context.Context, filter map[string]any) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for id, doc := range s.docs {
+		if matchesFilter(doc.Metadata, filter) {
+			delete(s.docs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// defaultGCInterval is used by StartGC when the interval is not positive.
+const defaultGCInterval = time.Minute
+
+// StartGC prunes expired documents every interval (default: 1 minute) until
+// the returned stop function is called.
+func (s *MemoryStore) StartGC(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultGCInterval
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.pruneExpired()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+func (s *MemoryStore) pruneExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, doc := range s.docs {
+		if doc.Expired(now) {
+			delete(s.docs, id)
+		}
+	}
+}
+
+// Snapshot marshals every document, including embeddings and expired ones,
+// to JSON sorted by ID so snapshots diff cleanly when committed as fixtures.
+func (s *MemoryStore) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	docs := make([]Document, 0, len(s.docs))
+	for _, doc := range s.docs {
+		docs = append(docs, doc)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Restore replaces the store's contents with the documents in a Snapshot.
+func (s *MemoryStore) Restore(data []byte) error {
+	var docs []Document
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return fmt.Errorf("unmarshal snapshot: %w", err)
+	}
+
+	restored := make(map[string]Document, len(docs))
+	for _, doc := range docs {
+		restored[doc.ID] = doc
+	}
+
+	s.mu.Lock()
+	s.docs = restored
+	s.mu.Unlock()
+	return nil
+}
+
+// SaveToFile writes a Snapshot to path.
+func (s *MemoryStore) SaveToFile(path string) error {
+	data, err := s.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile restores the store from a snapshot written by SaveToFile.
+func (s *MemoryStore) LoadFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	return s.Restore(data)
+}
+
 // Close is a no-op for in-memory store.
 func (s *MemoryStore) Close() error {
 	return nil
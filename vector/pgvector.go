@@ -10,16 +10,59 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// Distance metrics supported by PgVectorStore.
+const (
+	DistanceCosine       = "cosine"
+	DistanceL2           = "l2"
+	DistanceInnerProduct = "inner_product"
+)
+
+// PgVectorConfig configures a PgVectorStore.
+type PgVectorConfig struct {
+	DSN            string
+	Dimension      int    // Embedding dimension, e.g. 1536 for OpenAI
+	DistanceMetric string // Optional: DistanceCosine, DistanceL2 or DistanceInnerProduct (default: DistanceCosine)
+}
+
+// pgDistance is how one metric maps onto pgvector.
+type pgDistance struct {
+	opclass  string // HNSW operator class
+	operator string // distance operator, smaller is closer
+	index    string
+	score    string // SQL expression normalizing the distance d to a 0-1 score
+}
+
+// Scores are normalized to 0-1, higher meaning more similar, from the
+// pgvector distance d of each metric:
+//
+//	cosine:        score = max(0, 1 - d)   d = 1 - cosine similarity, so this is the similarity floored at 0
+//	l2:            score = 1 / (1 + d)     d = Euclidean distance in [0, inf)
+//	inner_product: score = 1 / (1 + e^d)   d = -(a . b), so this is the logistic sigmoid of the inner product
+var pgDistances = map[string]pgDistance{
+	DistanceCosine:       {"vector_cosine_ops", "<=>", "idx_documents_embedding", "GREATEST(0, 1 - (%s))"},
+	DistanceL2:           {"vector_l2_ops", "<->", "idx_documents_embedding_l2", "1 / (1 + (%s))"},
+	DistanceInnerProduct: {"vector_ip_ops", "<#>", "idx_documents_embedding_ip", "1 / (1 + EXP(%s))"},
+}
+
 // PgVectorStore is a PostgreSQL-based vector store using pgvector.
 type PgVectorStore struct {
 	db        *sql.DB
 	dimension int
+	distance  pgDistance
 }
 
 // NewPgVectorStore creates a new pgvector-based store.
-// The dimension parameter specifies the embedding dimension (e.g., 1536 for OpenAI).
-func NewPgVectorStore(dsn string, dimension int) (*PgVectorStore, error) {
-	db, err := sql.Open("pgx", dsn)
+func NewPgVectorStore(cfg PgVectorConfig) (*PgVectorStore, error) {
+	metric := cfg.DistanceMetric
+	if metric == "" {
+		metric = DistanceCosine
+	}
+	distance, ok := pgDistances[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown distance metric %q", cfg.DistanceMetric)
+	}
+
+	db, err := sql.Open("pgx", cfg.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -29,7 +72,7 @@ func NewPgVectorStore(dsn string, dimension int) (*PgVectorStore, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	store := &PgVectorStore{db: db, dimension: dimension}
+	store := &PgVectorStore{db: db, dimension: cfg.Dimension, distance: distance}
 	if err := store.migrate(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("migrate: %w", err)
@@ -48,7 +91,8 @@ func (s *PgVectorStore) migrate() error {
 			metadata JSONB DEFAULT '{}',
 			created_at TIMESTAMPTZ DEFAULT NOW()
 		)`, s.dimension),
-		`CREATE INDEX IF NOT EXISTS idx_documents_embedding ON documents USING hnsw (embedding vector_cosine_ops)`,
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON documents USING hnsw (embedding %s)`, s.distance.index, s.distance.opclass),
+		`ALTER TABLE documents ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ`,
 	}
 
 	for _, m := range migrations {
@@ -71,13 +115,14 @@ func (s *PgVectorStore) Upsert(ctx context.Context, docs []Document) error {
 		embeddingStr := formatEmbedding(doc.Embedding)
 
 		_, err = s.db.ExecContext(ctx, `
-			INSERT INTO documents (id, content, embedding, metadata)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO documents (id, content, embedding, metadata, expires_at)
+			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (id) DO UPDATE SET
 				content = EXCLUDED.content,
 				embedding = EXCLUDED.embedding,
-				metadata = EXCLUDED.metadata
-		`, doc.ID, doc.Content, embeddingStr, metadata)
+				metadata = EXCLUDED.metadata,
+				expires_at = EXCLUDED.expires_at
+		`, doc.ID, doc.Content, embeddingStr, metadata, doc.ExpiresAt)
 		if err != nil {
 			return fmt.Errorf("upsert document: %w", err)
 		}
@@ -87,14 +132,29 @@ func (s *PgVectorStore) Upsert(ctx context.Context, docs []Document) error {
 
 // Search finds documents similar to the given embedding.
 func (s *PgVectorStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	return s.SearchWithThreshold(ctx, embedding, SearchOptions{TopK: topK, MinScore: -1})
+}
+
+// SearchWithThreshold finds similar documents, dropping those scoring below opts.MinScore.
+// opts.Filter is matched with JSONB containment on the metadata column.
+func (s *PgVectorStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
 	embeddingStr := formatEmbedding(embedding)
 
+	filter, err := marshalFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	distance := "embedding " + s.distance.operator + " $1"
+	score := fmt.Sprintf(s.distance.score, distance)
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, content, embedding, metadata, 1 - (embedding <=> $1) AS score
+		SELECT id, content, embedding, metadata, expires_at, `+score+` AS score
 		FROM documents
-		ORDER BY embedding <=> $1
+		WHERE `+score+` >= $3 AND metadata @> $4::jsonb
+			AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY `+distance+`
 		LIMIT $2
-	`, embeddingStr, topK)
+	`, embeddingStr, opts.TopK, opts.MinScore, filter)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -107,7 +167,7 @@ func (s *PgVectorStore) Search(ctx context.Context, embedding []float64, topK in
 		var metadataBytes []byte
 		var score float64
 
-		if err := rows.Scan(&doc.ID, &doc.Content, &embeddingStr, &metadataBytes, &score); err != nil {
+		if err := rows.Scan(&doc.ID, &doc.Content, &embeddingStr, &metadataBytes, &doc.ExpiresAt, &score); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
@@ -125,6 +185,50 @@ func (s *PgVectorStore) Search(ctx context.Context, embedding []float64, topK in
 	return results, rows.Err()
 }
 
+// List returns unexpired documents in insertion order along with their total count.
+func (s *PgVectorStore) List(ctx context.Context, limit, offset int) ([]Document, int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE expires_at IS NULL OR expires_at > NOW()`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count: %w", err)
+	}
+
+	var limitArg any
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, content, embedding, metadata, expires_at
+		FROM documents
+		WHERE expires_at IS NULL OR expires_at > NOW()
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2
+	`, limitArg, max(offset, 0))
+	if err != nil {
+		return nil, 0, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []Document
+	for rows.Next() {
+		var doc Document
+		var embeddingStr string
+		var metadataBytes []byte
+
+		if err := rows.Scan(&doc.ID, &doc.Content, &embeddingStr, &metadataBytes, &doc.ExpiresAt); err != nil {
+			return nil, 0, fmt.Errorf("scan row: %w", err)
+		}
+
+		doc.Embedding = parseEmbedding(embeddingStr)
+		if len(metadataBytes) > 0 {
+			json.Unmarshal(metadataBytes, &doc.Metadata)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, total, rows.Err()
+}
+
 // Delete removes documents by ID.
 func (s *PgVectorStore) Delete(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
@@ -143,11 +247,43 @@ func (s *PgVectorStore) Delete(ctx context.Context, ids []string) error {
 	return err
 }
 
+// DeleteWhere removes documents whose metadata contains filter.
+func (s *PgVectorStore) DeleteWhere(ctx context.Context, filter map[string]any) (int, error) {
+	f, err := marshalFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `DELETE FROM documents WHERE metadata @> $1::jsonb RETURNING id`, f)
+	if err != nil {
+		return 0, fmt.Errorf("delete: %w", err)
+	}
+	defer rows.Close()
+
+	deleted := 0
+	for rows.Next() {
+		deleted++
+	}
+	return deleted, rows.Err()
+}
+
 // Close closes the database connection.
 func (s *PgVectorStore) Close() error {
 	return s.db.Close()
 }
 
+// marshalFilter encodes a metadata filter for JSONB containment; nil matches everything.
+func marshalFilter(filter map[string]any) ([]byte, error) {
+	if len(filter) == 0 {
+		return []byte("{}"), nil
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("marshal filter: %w", err)
+	}
+	return data, nil
+}
+
 // formatEmbedding converts a float64 slice to pgvector format: "[0.1,0.2,0.3]"
 func formatEmbedding(embedding []float64) string {
 	if len(embedding) == 0 {
@@ -0,0 +1,421 @@
+package vector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Reserved metadata keys holding the document's own fields.
+const (
+	pineconeContentKey = "_content"
+	pineconeExpiresKey = "_expires_at" // unix seconds
+)
+
+// pineconeBatchSize is the number of vectors sent per upsert request.
+const pineconeBatchSize = 100
+
+// PineconeConfig configures a PineconeStore. Set Host for serverless indexes;
+// otherwise the host is looked up by IndexName, through the legacy controller
+// when Environment is set.
+type PineconeConfig struct {
+	APIKey      string
+	IndexName   string
+	Environment string // Optional: legacy pod environment, e.g. us-east1-gcp
+	Host        string // Optional: index host, e.g. my-index-abc123.svc.aped-4627-b74a.pinecone.io
+	Namespace   string // Optional: scopes every operation to one namespace
+}
+
+// PineconeStore is a vector store backed by the Pinecone REST API.
+type PineconeStore struct {
+	baseURL   string
+	apiKey    string
+	namespace string
+	client    *http.Client
+}
+
+// NewPineconeStore resolves the index host and returns a store for it.
+func NewPineconeStore(cfg PineconeConfig) (*PineconeStore, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("pinecone: API key is required")
+	}
+
+	s := &PineconeStore{
+		apiKey:    cfg.APIKey,
+		namespace: cfg.Namespace,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	host := cfg.Host
+	if host == "" {
+		if cfg.IndexName == "" {
+			return nil, fmt.Errorf("pinecone: host or index name is required")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var err error
+		if host, err = s.describeHost(ctx, cfg); err != nil {
+			return nil, fmt.Errorf("describe index: %w", err)
+		}
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	s.baseURL = strings.TrimSuffix(host, "/")
+	return s, nil
+}
+
+// describeHost looks up the index host from the control plane.
+func (s *PineconeStore) describeHost(ctx context.Context, cfg PineconeConfig) (string, error) {
+	if cfg.Environment != "" {
+		var legacy struct {
+			Status struct {
+				Host string `json:"host"`
+			} `json:"status"`
+		}
+		endpoint := fmt.Sprintf("https://controller.%s.pinecone.io/databases/%s", cfg.Environment, cfg.IndexName)
+		if err := s.expectOK(ctx, http.MethodGet, endpoint, nil, &legacy); err != nil {
+			return "", err
+		}
+		return legacy.Status.Host, nil
+	}
+
+	var index struct {
+		Host string `json:"host"`
+	}
+	if err := s.expectOK(ctx, http.MethodGet, "https://api.pinecone.io/indexes/"+cfg.IndexName, nil, &index); err != nil {
+		return "", err
+	}
+	return index.Host, nil
+}
+
+type pineconeVector struct {
+	ID       string         `json:"id"`
+	Values   []float64      `json:"values,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Upsert stores documents in batches of 100, updating existing ones by ID.
+func (s *PineconeStore) Upsert(ctx context.Context, docs []Document) error {
+	for start := 0; start < len(docs); start += pineconeBatchSize {
+		batch := docs[start:min(start+pineconeBatchSize, len(docs))]
+
+		vectors := make([]pineconeVector, len(batch))
+		for i, doc := range batch {
+			metadata := make(map[string]any, len(doc.Metadata)+2)
+			for k, v := range doc.Metadata {
+				metadata[k] = v
+			}
+			metadata[pineconeContentKey] = doc.Content
+			if doc.ExpiresAt != nil {
+				metadata[pineconeExpiresKey] = doc.ExpiresAt.Unix()
+			}
+			vectors[i] = pineconeVector{ID: doc.ID, Values: doc.Embedding, Metadata: metadata}
+		}
+
+		body := s.withNamespace(map[string]any{"vectors": vectors})
+		if err := s.expectOK(ctx, http.MethodPost, s.baseURL+"/vectors/upsert", body, nil); err != nil {
+			return fmt.Errorf("upsert vectors: %w", err)
+		}
+	}
+	return nil
+}
+
+// Search finds documents similar to the given embedding.
+func (s *PineconeStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	return s.SearchWithThreshold(ctx, embedding, SearchOptions{TopK: topK, MinScore: -1})
+}
+
+// SearchWithThreshold finds similar documents, dropping those scoring below
+// opts.MinScore. Pinecone requires a topK, so 0 is treated as 10.
+func (s *PineconeStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
+	topK := opts.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+	body := s.withNamespace(map[string]any{
+		"vector":          embedding,
+		"topK":            topK,
+		"includeMetadata": true,
+		"includeValues":   true,
+		"filter":          pineconeFilter(opts.Filter),
+	})
+
+	var resp struct {
+		Matches []struct {
+			pineconeVector
+			Score float64 `json:"score"`
+		} `json:"matches"`
+	}
+	if err := s.expectOK(ctx, http.MethodPost, s.baseURL+"/query", body, &resp); err != nil {
+		return nil, fmt.Errorf("query vectors: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(resp.Matches))
+	for _, m := range resp.Matches {
+		if m.Score < opts.MinScore {
+			continue
+		}
+		results = append(results, SearchResult{Document: documentFromPinecone(m.pineconeVector), Score: m.Score})
+	}
+	return results, nil
+}
+
+// List pages through vector IDs in Pinecone's order and fetches the page.
+// Listing IDs is only supported on serverless indexes.
+func (s *PineconeStore) List(ctx context.Context, limit, offset int) ([]Document, int, error) {
+	total, err := s.count(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset = min(max(offset, 0), total)
+	want := total
+	if limit > 0 {
+		want = min(offset+limit, total)
+	}
+
+	var ids []string
+	token := ""
+	for len(ids) < want {
+		q := url.Values{}
+		if s.namespace != "" {
+			q.Set("namespace", s.namespace)
+		}
+		if token != "" {
+			q.Set("paginationToken", token)
+		}
+
+		var page struct {
+			Vectors []struct {
+				ID string `json:"id"`
+			} `json:"vectors"`
+			Pagination struct {
+				Next string `json:"next"`
+			} `json:"pagination"`
+		}
+		if err := s.expectOK(ctx, http.MethodGet, s.baseURL+"/vectors/list?"+q.Encode(), nil, &page); err != nil {
+			return nil, 0, fmt.Errorf("list vectors: %w", err)
+		}
+		for _, v := range page.Vectors {
+			ids = append(ids, v.ID)
+		}
+		if token = page.Pagination.Next; token == "" {
+			break
+		}
+	}
+	if offset >= len(ids) {
+		return []Document{}, total, nil
+	}
+
+	docs, err := s.fetch(ctx, ids[offset:min(want, len(ids))])
+	if err != nil {
+		return nil, 0, err
+	}
+	return docs, total, nil
+}
+
+// fetch loads vectors by ID, preserving the order of ids.
+func (s *PineconeStore) fetch(ctx context.Context, ids []string) ([]Document, error) {
+	q := url.Values{"ids": ids}
+	if s.namespace != "" {
+		q.Set("namespace", s.namespace)
+	}
+
+	var resp struct {
+		Vectors map[string]pineconeVector `json:"vectors"`
+	}
+	if err := s.expectOK(ctx, http.MethodGet, s.baseURL+"/vectors/fetch?"+q.Encode(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("fetch vectors: %w", err)
+	}
+
+	docs := make([]Document, 0, len(ids))
+	for _, id := range ids {
+		if v, ok := resp.Vectors[id]; ok {
+			docs = append(docs, documentFromPinecone(v))
+		}
+	}
+	return docs, nil
+}
+
+// Delete removes documents by ID.
+func (s *PineconeStore) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	body := s.withNamespace(map[string]any{"ids": ids})
+	if err := s.expectOK(ctx, http.MethodPost, s.baseURL+"/vectors/delete", body, nil); err != nil {
+		return fmt.Errorf("delete vectors: %w", err)
+	}
+	return nil
+}
+
+// DeleteWhere removes vectors whose metadata matches filter. Pinecone doesn't
+// report deletions, so matches are counted first; serverless indexes reject
+// both filtered counts and filtered deletes.
+func (s *PineconeStore) DeleteWhere(ctx context.Context, filter map[string]any) (int, error) {
+	matched, err := s.count(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	if matched == 0 {
+		return 0, nil
+	}
+
+	body := map[string]any{"deleteAll": true}
+	if len(filter) > 0 {
+		body = map[string]any{"filter": pineconeMatch(filter)}
+	}
+	if err := s.expectOK(ctx, http.MethodPost, s.baseURL+"/vectors/delete", s.withNamespace(body), nil); err != nil {
+		return 0, fmt.Errorf("delete vectors: %w", err)
+	}
+	return matched, nil
+}
+
+func (s *PineconeStore) count(ctx context.Context, filter map[string]any) (int, error) {
+	body := map[string]any{}
+	if len(filter) > 0 {
+		body["filter"] = pineconeMatch(filter)
+	}
+
+	var stats struct {
+		TotalVectorCount int `json:"totalVectorCount"`
+		Namespaces       map[string]struct {
+			VectorCount int `json:"vectorCount"`
+		} `json:"namespaces"`
+	}
+	if err := s.expectOK(ctx, http.MethodPost, s.baseURL+"/describe_index_stats", body, &stats); err != nil {
+		return 0, fmt.Errorf("describe index stats: %w", err)
+	}
+	if s.namespace == "" && len(stats.Namespaces) == 0 {
+		return stats.TotalVectorCount, nil
+	}
+	return stats.Namespaces[s.namespace].VectorCount, nil
+}
+
+// Close is a no-op; the REST client holds no persistent connection.
+func (s *PineconeStore) Close() error {
+	return nil
+}
+
+func (s *PineconeStore) withNamespace(body map[string]any) map[string]any {
+	if s.namespace != "" {
+		body["namespace"] = s.namespace
+	}
+	return body
+}
+
+// pineconeMatch translates a metadata filter into Pinecone's filter syntax:
+// scalars become $eq, slices $in, and operator objects such as
+// {"$gte": 3} pass through unchanged.
+func pineconeMatch(filter map[string]any) map[string]any {
+	conditions := make([]map[string]any, 0, len(filter))
+	for k, v := range filter {
+		var cond any
+		switch {
+		case isOperatorMap(v):
+			cond = v
+		case v != nil && reflect.TypeOf(v).Kind() == reflect.Slice:
+			cond = map[string]any{"$in": v}
+		default:
+			cond = map[string]any{"$eq": v}
+		}
+		conditions = append(conditions, map[string]any{k: cond})
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return map[string]any{"$and": conditions}
+}
+
+// pineconeFilter is pineconeMatch plus a condition excluding expired vectors.
+func pineconeFilter(filter map[string]any) map[string]any {
+	unexpired := map[string]any{"$or": []map[string]any{
+		{pineconeExpiresKey: map[string]any{"$exists": false}},
+		{pineconeExpiresKey: map[string]any{"$gt": time.Now().Unix()}},
+	}}
+	if len(filter) == 0 {
+		return unexpired
+	}
+	return map[string]any{"$and": []map[string]any{pineconeMatch(filter), unexpired}}
+}
+
+func isOperatorMap(v any) bool {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		return false
+	}
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PineconeStore) expectOK(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Api-Key", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Pinecone API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func documentFromPinecone(v pineconeVector) Document {
+	doc := Document{ID: v.ID, Embedding: v.Values}
+	doc.Content, _ = v.Metadata[pineconeContentKey].(string)
+	if secs, ok := v.Metadata[pineconeExpiresKey].(float64); ok {
+		expiresAt := time.Unix(int64(secs), 0)
+		doc.ExpiresAt = &expiresAt
+	}
+
+	for k, val := range v.Metadata {
+		if k == pineconeContentKey || k == pineconeExpiresKey {
+			continue
+		}
+		if doc.Metadata == nil {
+			doc.Metadata = make(map[string]any)
+		}
+		doc.Metadata[k] = val
+	}
+	return doc
+}
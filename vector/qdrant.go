@@ -0,0 +1,350 @@
+package vector
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Reserved payload keys holding the document's own fields; all other
+// payload keys are metadata.
+const (
+	qdrantIDKey      = "_id"
+	qdrantContentKey = "_content"
+	qdrantExpiresKey = "_expires_at" // unix seconds
+)
+
+// QdrantConfig configures a QdrantStore.
+type QdrantConfig struct {
+	Host           string // Default: localhost
+	Port           int    // Default: 6333 (REST)
+	CollectionName string
+	APIKey         string // Optional: sent as the api-key header
+	Dimension      int    // Embedding dimension used when creating the collection
+}
+
+// QdrantStore is a vector store backed by the Qdrant REST API.
+type QdrantStore struct {
+	baseURL    string
+	collection string
+	apiKey     string
+	client     *http.Client
+}
+
+// NewQdrantStore connects to Qdrant and creates the collection (cosine distance) if it doesn't exist.
+func NewQdrantStore(cfg QdrantConfig) (*QdrantStore, error) {
+	if cfg.CollectionName == "" {
+		return nil, fmt.Errorf("qdrant: collection name is required")
+	}
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 6333
+	}
+
+	s := &QdrantStore{
+		baseURL:    fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port),
+		collection: cfg.CollectionName,
+		apiKey:     cfg.APIKey,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.ensureCollection(ctx, cfg.Dimension); err != nil {
+		return nil, fmt.Errorf("ensure collection: %w", err)
+	}
+	return s, nil
+}
+
+func (s *QdrantStore) ensureCollection(ctx context.Context, dimension int) error {
+	status, _, err := s.do(ctx, http.MethodGet, "/collections/"+s.collection, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+	if status != http.StatusNotFound {
+		return fmt.Errorf("get collection: status %d", status)
+	}
+
+	if dimension <= 0 {
+		return fmt.Errorf("dimension is required to create collection %s", s.collection)
+	}
+	body := map[string]any{
+		"vectors": map[string]any{"size": dimension, "distance": "Cosine"},
+	}
+	return s.expectOK(ctx, http.MethodPut, "/collections/"+s.collection, body, nil)
+}
+
+type qdrantPoint struct {
+	ID      string         `json:"id"`
+	Vector  []float64      `json:"vector,omitempty"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+// Upsert stores documents, updating existing ones by ID.
+func (s *QdrantStore) Upsert(ctx context.Context, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	points := make([]qdrantPoint, len(docs))
+	for i, doc := range docs {
+		payload := make(map[string]any, len(doc.Metadata)+2)
+		for k, v := range doc.Metadata {
+			payload[k] = v
+		}
+		payload[qdrantIDKey] = doc.ID
+		payload[qdrantContentKey] = doc.Content
+		if doc.ExpiresAt != nil {
+			payload[qdrantExpiresKey] = doc.ExpiresAt.Unix()
+		}
+		points[i] = qdrantPoint{ID: qdrantPointID(doc.ID), Vector: doc.Embedding, Payload: payload}
+	}
+
+	path := "/collections/" + s.collection + "/points?wait=true"
+	if err := s.expectOK(ctx, http.MethodPut, path, map[string]any{"points": points}, nil); err != nil {
+		return fmt.Errorf("upsert points: %w", err)
+	}
+	return nil
+}
+
+// Search finds documents similar to the given embedding.
+func (s *QdrantStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	return s.SearchWithThreshold(ctx, embedding, SearchOptions{TopK: topK, MinScore: -1})
+}
+
+// SearchWithThreshold finds similar documents, dropping those scoring below opts.MinScore.
+// opts.Filter is translated to Qdrant "must" match conditions on payload fields.
+func (s *QdrantStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
+	body := map[string]any{
+		"vector":       embedding,
+		"with_payload": true,
+		"with_vector":  true,
+		"filter":       qdrantFilter(opts.Filter),
+	}
+	if opts.TopK > 0 {
+		body["limit"] = opts.TopK
+	}
+	if opts.MinScore > -1 {
+		body["score_threshold"] = opts.MinScore
+	}
+
+	var resp struct {
+		Result []struct {
+			Score   float64        `json:"score"`
+			Vector  []float64      `json:"vector"`
+			Payload map[string]any `json:"payload"`
+		} `json:"result"`
+	}
+	path := "/collections/" + s.collection + "/points/search"
+	if err := s.expectOK(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return nil, fmt.Errorf("search points: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(resp.Result))
+	for _, r := range resp.Result {
+		results = append(results, SearchResult{
+			Document: documentFromPayload(r.Payload, r.Vector),
+			Score:    r.Score,
+		})
+	}
+	return results, nil
+}
+
+// List pages through points in Qdrant's point ID order. Qdrant scrolls by
+// cursor rather than offset, so the skipped points are fetched and discarded.
+func (s *QdrantStore) List(ctx context.Context, limit, offset int) ([]Document, int, error) {
+	total, err := s.count(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset = min(max(offset, 0), total)
+	want := total
+	if limit > 0 {
+		want = min(offset+limit, total)
+	}
+	if want == offset {
+		return []Document{}, total, nil
+	}
+
+	var scroll struct {
+		Result struct {
+			Points []struct {
+				Vector  []float64      `json:"vector"`
+				Payload map[string]any `json:"payload"`
+			} `json:"points"`
+		} `json:"result"`
+	}
+	body := map[string]any{
+		"limit":        want,
+		"with_payload": true,
+		"with_vector":  true,
+		"filter":       qdrantFilter(nil),
+	}
+	path := "/collections/" + s.collection + "/points/scroll"
+	if err := s.expectOK(ctx, http.MethodPost, path, body, &scroll); err != nil {
+		return nil, 0, fmt.Errorf("scroll points: %w", err)
+	}
+
+	points := scroll.Result.Points
+	docs := make([]Document, 0, len(points))
+	for i := offset; i < len(points); i++ {
+		docs = append(docs, documentFromPayload(points[i].Payload, points[i].Vector))
+	}
+	return docs, total, nil
+}
+
+// Delete removes documents by ID.
+func (s *QdrantStore) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = qdrantPointID(id)
+	}
+
+	path := "/collections/" + s.collection + "/points/delete?wait=true"
+	if err := s.expectOK(ctx, http.MethodPost, path, map[string]any{"points": pointIDs}, nil); err != nil {
+		return fmt.Errorf("delete points: %w", err)
+	}
+	return nil
+}
+
+// DeleteWhere removes points whose payload matches filter. Qdrant doesn't
+// report deletions, so matching points are counted first.
+func (s *QdrantStore) DeleteWhere(ctx context.Context, filter map[string]any) (int, error) {
+	matched, err := s.count(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	if matched == 0 {
+		return 0, nil
+	}
+
+	path := "/collections/" + s.collection + "/points/delete?wait=true"
+	if err := s.expectOK(ctx, http.MethodPost, path, map[string]any{"filter": qdrantFilter(filter)}, nil); err != nil {
+		return 0, fmt.Errorf("delete points: %w", err)
+	}
+	return matched, nil
+}
+
+func (s *QdrantStore) count(ctx context.Context, filter map[string]any) (int, error) {
+	var resp struct {
+		Result struct {
+			Count int `json:"count"`
+		} `json:"result"`
+	}
+	body := map[string]any{"exact": true, "filter": qdrantFilter(filter)}
+	path := "/collections/" + s.collection + "/points/count"
+	if err := s.expectOK(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return 0, fmt.Errorf("count points: %w", err)
+	}
+	return resp.Result.Count, nil
+}
+
+// qdrantFilter translates a metadata filter into "must" match conditions on
+// payload fields and excludes expired points.
+func qdrantFilter(filter map[string]any) map[string]any {
+	must := make([]map[string]any, 0, len(filter))
+	for k, v := range filter {
+		must = append(must, map[string]any{"key": k, "match": map[string]any{"value": v}})
+	}
+	expired := map[string]any{"key": qdrantExpiresKey, "range": map[string]any{"lte": time.Now().Unix()}}
+	return map[string]any{"must": must, "must_not": []map[string]any{expired}}
+}
+
+// Close is a no-op; the REST client holds no persistent connection.
+func (s *QdrantStore) Close() error {
+	return nil
+}
+
+func (s *QdrantStore) expectOK(ctx context.Context, method, path string, body, out any) error {
+	status, respBody, err := s.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("Qdrant API error (status %d): %s", status, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func (s *QdrantStore) do(ctx context.Context, method, path string, body any) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("api-key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func documentFromPayload(payload map[string]any, vec []float64) Document {
+	doc := Document{Embedding: vec}
+	doc.ID, _ = payload[qdrantIDKey].(string)
+	doc.Content, _ = payload[qdrantContentKey].(string)
+	if secs, ok := payload[qdrantExpiresKey].(float64); ok {
+		expiresAt := time.Unix(int64(secs), 0)
+		doc.ExpiresAt = &expiresAt
+	}
+
+	for k, v := range payload {
+		if k == qdrantIDKey || k == qdrantContentKey || k == qdrantExpiresKey {
+			continue
+		}
+		if doc.Metadata == nil {
+			doc.Metadata = make(map[string]any)
+		}
+		doc.Metadata[k] = v
+	}
+	return doc
+}
+
+// qdrantPointID maps an arbitrary document ID to the UUID form Qdrant requires.
+func qdrantPointID(id string) string {
+	sum := sha1.Sum([]byte(id))
+	sum[6] = (sum[6] & 0x0f) | 0x50 // version 5
+	sum[8] = (sum[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,210 @@
+package vector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hubenschmidt/go-fissio/llm"
+)
+
+// Reranker reorders search results by relevance to query. Implementations
+// replace SearchResult.Score with their own relevance score.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error)
+}
+
+const rerankPrompt = "You rate how relevant a document is to a search query. " +
+	"Respond with only a number from 0 (irrelevant) to 10 (directly answers the query)."
+
+// LLMReranker scores each query/document pair with a chat model.
+type LLMReranker struct {
+	client llm.Client
+	model  string
+}
+
+func NewLLMReranker(client llm.Client, model string) *LLMReranker {
+	return &LLMReranker{client: client, model: model}
+}
+
+// Rerank scores all candidates concurrently; scores are normalized to 0-1.
+func (r *LLMReranker) Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error) {
+	reranked := make([]SearchResult, len(results))
+	errs := make([]error, len(results))
+
+	var wg sync.WaitGroup
+	for i, res := range results {
+		wg.Add(1)
+		go func(i int, res SearchResult) {
+			defer wg.Done()
+			user := fmt.Sprintf("Query: %s\n\nDocument:\n%s", query, res.Document.Content)
+			resp, err := r.client.Chat(ctx, r.model, rerankPrompt, user)
+			if err != nil {
+				errs[i] = fmt.Errorf("score document %s: %w", res.Document.ID, err)
+				return
+			}
+			res.Score = parseRelevance(resp.Content) / 10
+			reranked[i] = res
+		}(i, res)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sortByScore(reranked)
+	return reranked, nil
+}
+
+// parseRelevance reads the leading number of a model reply, clamped to 0-10.
+func parseRelevance(reply string) float64 {
+	fields := strings.Fields(reply)
+	if len(fields) == 0 {
+		return 0
+	}
+	score, err := strconv.ParseFloat(strings.Trim(fields[0], ".,*"), 64)
+	if err != nil {
+		return 0
+	}
+	return min(max(score, 0), 10)
+}
+
+// CohereReranker reranks with the Cohere /v1/rerank API.
+type CohereReranker struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewCohereReranker creates a Cohere reranker; model defaults to rerank-english-v3.0.
+func NewCohereReranker(apiKey, model string) *CohereReranker {
+	if model == "" {
+		model = "rerank-english-v3.0"
+	}
+	return &CohereReranker{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: "https://api.cohere.ai",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (r *CohereReranker) Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	docs := make([]string, len(results))
+	for i, res := range results {
+		docs[i] = res.Document.Content
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":     r.model,
+		"query":     query,
+		"documents": docs,
+		"top_n":     len(docs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/v1/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Cohere API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	reranked := make([]SearchResult, 0, len(result.Results))
+	for _, rr := range result.Results {
+		if rr.Index < 0 || rr.Index >= len(results) {
+			continue
+		}
+		res := results[rr.Index]
+		res.Score = rr.RelevanceScore
+		reranked = append(reranked, res)
+	}
+	sortByScore(reranked)
+	return reranked, nil
+}
+
+// RerankingStore wraps a Store and reranks its search results. The query
+// text comes from ctx (see WithQueryText); without it results pass through
+// in their original order.
+type RerankingStore struct {
+	Store
+	reranker Reranker
+}
+
+// NewRerankingStore wraps store so Search and SearchWithThreshold rerank with r.
+func NewRerankingStore(store Store, r Reranker) *RerankingStore {
+	return &RerankingStore{Store: store, reranker: r}
+}
+
+func (s *RerankingStore) Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error) {
+	results, err := s.Store.Search(ctx, embedding, topK)
+	if err != nil {
+		return nil, err
+	}
+	return s.rerank(ctx, results)
+}
+
+func (s *RerankingStore) SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error) {
+	results, err := s.Store.SearchWithThreshold(ctx, embedding, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.rerank(ctx, results)
+}
+
+func (s *RerankingStore) rerank(ctx context.Context, results []SearchResult) ([]SearchResult, error) {
+	query := queryText(ctx)
+	if query == "" || len(results) <= 1 {
+		return results, nil
+	}
+	reranked, err := s.reranker.Rerank(ctx, query, results)
+	if err != nil {
+		return nil, fmt.Errorf("rerank: %w", err)
+	}
+	return reranked, nil
+}
+
+func sortByScore(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
@@ -0,0 +1,132 @@
+package vector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSeparators are tried in order from coarsest (paragraphs) to finest (characters).
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " ", ""}
+
+// RecursiveTextSplitter splits text on the coarsest separator that yields
+// chunks within ChunkSize, recursing into finer separators only for pieces
+// that are still too long.
+type RecursiveTextSplitter struct {
+	ChunkSize    int              // Max chunk length (default: 1000)
+	ChunkOverlap int              // Optional: length carried over from the end of the previous chunk
+	Separators   []string         // Optional: defaults to DefaultSeparators
+	Length       func(string) int // Optional: length measure (default: characters); see EstimateTokens
+}
+
+// EstimateTokens approximates the token count of s at ~4 characters per token.
+// Use it as RecursiveTextSplitter.Length to size chunks in tokens.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// Split returns the chunks of text in order.
+func (s *RecursiveTextSplitter) Split(text string) []string {
+	seps := s.Separators
+	if len(seps) == 0 {
+		seps = DefaultSeparators
+	}
+	return s.split(text, seps)
+}
+
+func (s *RecursiveTextSplitter) split(text string, seps []string) []string {
+	sep, rest := seps[len(seps)-1], []string(nil)
+	for i, candidate := range seps {
+		if candidate == "" || strings.Contains(text, candidate) {
+			sep, rest = candidate, seps[i+1:]
+			break
+		}
+	}
+
+	// SplitAfter keeps each separator (e.g. the period of ". ") with its piece.
+	var chunks, fitting []string
+	for _, piece := range strings.SplitAfter(text, sep) {
+		if s.length(piece) <= s.chunkSize() {
+			fitting = append(fitting, piece)
+			continue
+		}
+		chunks = append(chunks, s.merge(fitting)...)
+		fitting = nil
+		if len(rest) == 0 {
+			chunks = appendChunk(chunks, piece)
+			continue
+		}
+		chunks = append(chunks, s.split(piece, rest)...)
+	}
+	return append(chunks, s.merge(fitting)...)
+}
+
+// merge joins consecutive pieces into chunks of at most ChunkSize, starting
+// each new chunk with up to ChunkOverlap of the previous one.
+func (s *RecursiveTextSplitter) merge(pieces []string) []string {
+	size, overlap := s.chunkSize(), s.ChunkOverlap
+
+	var chunks, current []string
+	total := 0
+	for _, piece := range pieces {
+		pieceLen := s.length(piece)
+		if len(current) > 0 && total+pieceLen > size {
+			chunks = appendChunk(chunks, strings.Join(current, ""))
+			for len(current) > 0 && (total > overlap || total+pieceLen > size) {
+				total -= s.length(current[0])
+				current = current[1:]
+			}
+		}
+		total += pieceLen
+		current = append(current, piece)
+	}
+
+	if len(current) > 0 {
+		chunks = appendChunk(chunks, strings.Join(current, ""))
+	}
+	return chunks
+}
+
+func (s *RecursiveTextSplitter) chunkSize() int {
+	if s.ChunkSize <= 0 {
+		return 1000
+	}
+	return s.ChunkSize
+}
+
+func (s *RecursiveTextSplitter) length(text string) int {
+	if s.Length != nil {
+		return s.Length(text)
+	}
+	return len([]rune(text))
+}
+
+func appendChunk(chunks []string, chunk string) []string {
+	if chunk = strings.TrimSpace(chunk); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// SplitDocument splits doc's content into chunk documents with IDs "<id>#<n>".
+// Each chunk keeps doc's metadata and expiry, plus source_id, chunk_index and chunk_count.
+func SplitDocument(doc Document, s *RecursiveTextSplitter) []Document {
+	chunks := s.Split(doc.Content)
+	docs := make([]Document, len(chunks))
+	for i, chunk := range chunks {
+		metadata := make(map[string]any, len(doc.Metadata)+3)
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		metadata["source_id"] = doc.ID
+		metadata["chunk_index"] = i
+		metadata["chunk_count"] = len(chunks)
+
+		docs[i] = Document{
+			ID:        fmt.Sprintf("%s#%d", doc.ID, i),
+			Content:   chunk,
+			Metadata:  metadata,
+			ExpiresAt: doc.ExpiresAt,
+		}
+	}
+	return docs
+}
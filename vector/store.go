@@ -1,7 +1,10 @@
 // Package vector provides vector storage and similarity search.
 package vector
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Document represents a document with optional embedding.
 type Document struct {
@@ -9,6 +12,12 @@ type Document struct {
 	Content   string         `json:"content"`
 	Embedding []float64      `json:"embedding,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"` // Optional: hidden from search and list after this time
+}
+
+// Expired reports whether the document has an expiry at or before now.
+func (d Document) Expired(now time.Time) bool {
+	return d.ExpiresAt != nil && !d.ExpiresAt.After(now)
 }
 
 // SearchResult represents a search result with similarity score.
@@ -17,6 +26,14 @@ type SearchResult struct {
 	Score    float64  `json:"score"` // cosine similarity (0-1)
 }
 
+// SearchOptions controls result count and relevance filtering.
+type SearchOptions struct {
+	TopK     int     // Maximum results (0 = unlimited for MemoryStore)
+	MinScore float64 // Drop results with cosine similarity below this value
+
+	Filter map[string]any // Optional: metadata key -> value every result must match
+}
+
 // Store provides vector storage and similarity search operations.
 type Store interface {
 	// Upsert stores documents, updating existing ones by ID.
@@ -25,9 +42,20 @@ type Store interface {
 	// Search finds documents similar to the given embedding.
 	Search(ctx context.Context, embedding []float64, topK int) ([]SearchResult, error)
 
+	// SearchWithThreshold finds similar documents scoring at least opts.MinScore
+	// whose metadata matches opts.Filter.
+	SearchWithThreshold(ctx context.Context, embedding []float64, opts SearchOptions) ([]SearchResult, error)
+
+	// List returns a page of documents in a stable order, plus the total count.
+	List(ctx context.Context, limit, offset int) ([]Document, int, error)
+
 	// Delete removes documents by ID.
 	Delete(ctx context.Context, ids []string) error
 
+	// DeleteWhere removes documents whose metadata matches filter and
+	// returns how many were deleted. An empty filter matches every document.
+	DeleteWhere(ctx context.Context, filter map[string]any) (int, error)
+
 	// Close releases resources.
 	Close() error
 }